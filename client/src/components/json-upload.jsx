@@ -108,7 +108,7 @@ export default function JsonUpload() {
           qualifiers: Object.entries(item.qualifiers).map(
             ([qualifier, value]) => ({
               name: qualifier,
-              value: encodeURIComponent(value),
+              value,
             }),
           ),
         };
@@ -145,7 +145,7 @@ export default function JsonUpload() {
         family: chunk.family,
         qualifiers: Object.entries(chunk.qualifiers).map(([name, value]) => ({
           name,
-          value: encodeURIComponent(value),
+          value,
         })),
       };
 
@@ -73,7 +73,7 @@ export default function QueryBuilder() {
           // For WRITE operations, include both qualifier and value
           return {
             name: q.qualifier,
-            value: encodeURIComponent(q.value || ""),
+            value: q.value || "",
           };
         }),
     };
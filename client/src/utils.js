@@ -1,5 +1,5 @@
 /**
- * Unwraps the input data, decodes the value, and maintains the original structure.
+ * Unwraps the input data, decodes the base64 values as UTF-8, and maintains the original structure.
  * Ensures single rows include a `rowKey` key.
  * @param {Object} data - The input data object (single row or filter query).
  * @returns {Object} - An object with the same structure as the input, but with decoded values.
@@ -29,16 +29,15 @@ export function unwrapAndDecodeData(data) {
     return decodedCols;
   };
 
-  // Helper function to process values - first URL-decode, then handle potential JSON
+  // Helper function to process values - the server sends the stored bytes base64 encoded
   const processValue = (value) => {
     if (!value) return "";
 
     try {
       const binary = atob(value);
-      const utf8 = new TextDecoder("utf-8").decode(
+      return new TextDecoder("utf-8").decode(
         new Uint8Array([...binary].map((c) => c.charCodeAt(0))),
       );
-      return decodeURIComponent(utf8.replace(/\+/g, " "));
     } catch (e) {
       return value;
     }
@@ -1,32 +1,24 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/exitcode"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgKey                string
-	cfgVal                string
-	allowedConfigurations = []string{
-		"server_port",
-		"server_address",
-		"garbage_collection_timer",
-		"backup_timer",
-		"snapshot_timer",
-		"max_snapshot_limit",
-		"debug",
-		"cloud_environment",
-	}
-)
+	cfgKey string
+	cfgVal string
 
-// Config represents the structure of the config file
-type Config map[string]string
+	// allowedConfigurations can be changed with 'litetable config'. The other keys are
+	// written by 'service init', 'service update' and 'service use'.
+	allowedConfigurations = config.SettableKeys()
+)
 
 func init() {
 	rootCmd.AddCommand(configCmd)
@@ -45,14 +37,17 @@ func init() {
 	updateCmd.MarkFlagRequired("value")
 
 	configCmd.AddCommand(viewCmd)
-
+	configCmd.AddCommand(getCmd)
+	configCmd.AddCommand(unsetCmd)
+	configCmd.AddCommand(resetCmd)
+	configCmd.AddCommand(diffCmd)
 }
 
 // configCmd represents the config command
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage litetable configuration",
-	Long:  `Configure litetable settings through set and update operations.`,
+	Long:  `Configure litetable settings through set, update, unset and reset operations.`,
 }
 
 // viewCmd represents the view command
@@ -60,33 +55,31 @@ var viewCmd = &cobra.Command{
 	Use:   "view",
 	Short: "View the current configuration",
 	Long:  `Display the contents of the litetable.conf file.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		configPath, err := getConfigFilePath()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, err := config.Path()
 		if err != nil {
-			fmt.Printf("Error finding config file: %s\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to find config file: %w", err)
 		}
 
 		// Check if the file exists
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			fmt.Println("Configuration file does not exist.")
-			return
+			return exitcode.NotFoundError(fmt.Errorf("configuration file does not exist"))
 		}
 
 		// Read the file contents
 		data, err := os.ReadFile(configPath)
 		if err != nil {
-			fmt.Printf("Error reading config file: %s\n", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to read config file: %w", err)
 		}
 
 		if len(data) == 0 {
-			fmt.Println("Configuration file is empty.")
-			return
+			fmt.Fprintln(os.Stderr, "Configuration file is empty.")
+			return nil
 		}
 
 		// Print the raw content
 		fmt.Println(string(data))
+		return nil
 	},
 }
 
@@ -95,43 +88,31 @@ var setCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set a configuration value",
 	Long:  `Set a new configuration value. Will error if the key already exists.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if cfgKey == "" {
-			fmt.Println("Error: key is required")
-			return
-		}
-		if cfgVal == "" {
-			fmt.Println("Error: value is required")
-			return
-		}
-
-		// Validate if key is allowed
-		if !isAllowedConfigKey(cfgKey) {
-			fmt.Printf("Error: '%s' is not an allowed configuration key.\n", cfgKey)
-			fmt.Printf("Allowed keys: %s\n", strings.Join(allowedConfigurations, ", "))
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateConfigInput(); err != nil {
+			return err
 		}
 
 		// Load config
-		config, err := loadConfig()
+		f, err := loadConfigFile()
 		if err != nil {
-			fmt.Printf("Error loading config: %s\n", err)
-			return
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		// Check if key already exists
-		if _, exists := config[cfgKey]; exists {
-			fmt.Printf("Error: key '%s' already exists. Use 'update' to modify existing values.\n", cfgKey)
-			return
+		if _, exists := f.Get(cfgKey); exists {
+			return exitcode.UsageError(fmt.Errorf("key '%s' already exists. Use 'update' to modify existing values", cfgKey))
 		}
 
 		// Set the value
-		config[cfgKey] = cfgVal
-		if err := saveConfig(config); err != nil {
-			fmt.Printf("Error saving config: %s\n", err)
-			return
+		if err := f.Set(cfgKey, cfgVal); err != nil {
+			return exitcode.UsageError(err)
+		}
+		if err := f.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
 		}
 		fmt.Printf("Successfully set '%s' to '%s'\n", cfgKey, cfgVal)
+		return nil
 	},
 }
 
@@ -140,174 +121,235 @@ var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update an existing configuration value",
 	Long:  `Update an existing configuration value. Will error if the key does not exist.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if cfgKey == "" {
-			fmt.Println("Error: key is required")
-			return
-		}
-		if cfgVal == "" {
-			fmt.Println("Error: value is required")
-			return
-		}
-
-		// Validate if key is allowed
-		if !isAllowedConfigKey(cfgKey) {
-			fmt.Printf("Error: '%s' is not an allowed configuration key.\n", cfgKey)
-			fmt.Printf("Allowed keys: %s\n", strings.Join(allowedConfigurations, ", "))
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateConfigInput(); err != nil {
+			return err
 		}
 
 		// Load config
-		config, err := loadConfig()
+		f, err := loadConfigFile()
 		if err != nil {
-			fmt.Printf("Error loading config: %s\n", err)
-			return
+			return fmt.Errorf("failed to load config: %w", err)
 		}
 
 		// Check if key exists
-		if _, exists := config[cfgKey]; !exists {
-			fmt.Printf("Error: key '%s' does not exist. Use 'set' to create a new value.\n", cfgKey)
-			return
+		if _, exists := f.Get(cfgKey); !exists {
+			return exitcode.NotFoundError(fmt.Errorf("key '%s' does not exist. Use 'set' to create a new value", cfgKey))
 		}
 
 		// Update the value
-		config[cfgKey] = cfgVal
-		if err := saveConfig(config); err != nil {
-			fmt.Printf("Error updating config: %s\n", err)
-			return
+		if err := f.Set(cfgKey, cfgVal); err != nil {
+			return exitcode.UsageError(err)
+		}
+		if err := f.Save(); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
 		}
 		fmt.Printf("Successfully updated '%s' to '%s'\n", cfgKey, cfgVal)
+		return nil
 	},
 }
 
-// getConfigFilePath returns the path to the config file
-func getConfigFilePath() (string, error) {
-	home, err := dir.GetLitetableDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: "Print the value of a configuration key, or its default when litetable.conf does not " +
+		"set it. Exits with code 4 when the key has neither.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		if err := validateConfigKey(key); err != nil {
+			return err
+		}
 
-	return filepath.Join(home, "litetable.conf"), nil
+		f, err := loadInstalledConfig()
+		if err != nil {
+			return err
+		}
+
+		value, _ := f.Get(key)
+		if value == "" {
+			k, _ := config.Lookup(key)
+			value = k.Default
+		}
+		if value == "" {
+			return exitcode.NotFoundError(fmt.Errorf("key '%s' is not set", key))
+		}
+		fmt.Println(value)
+		return nil
+	},
 }
 
-// loadConfig reads the configuration from the config file
-func loadConfig() (Config, error) {
-	config := make(Config)
+// unsetCmd represents the unset command
+var unsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long:  `Remove a key from litetable.conf, so its default applies. Will error if the key does not exist.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		if err := validateConfigKey(key); err != nil {
+			return err
+		}
 
-	configPath, err := getConfigFilePath()
-	if err != nil {
-		return nil, err
-	}
+		f, err := loadInstalledConfig()
+		if err != nil {
+			return err
+		}
 
-	// If file doesn't exist, return empty config
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return config, nil
-	}
+		if !f.Unset(key) {
+			return exitcode.NotFoundError(fmt.Errorf("key '%s' does not exist", key))
+		}
+		if err := f.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("Successfully unset '%s'\n", key)
+		return nil
+	},
+}
 
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
+// resetCmd represents the reset command
+var resetCmd = &cobra.Command{
+	Use:   "reset [key]",
+	Short: "Reset configuration values to their defaults",
+	Long: "Reset a key, or every settable key, to the value 'litetable service init' writes. Keys " +
+		"without a default are removed. The server binary, version and install settings are kept.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keys := allowedConfigurations
+		if len(args) == 1 {
+			if err := validateConfigKey(args[0]); err != nil {
+				return err
+			}
+			keys = args[:1]
+		}
 
-	// Empty file case
-	if len(data) == 0 {
-		return config, nil
-	}
+		f, err := loadInstalledConfig()
+		if err != nil {
+			return err
+		}
 
-	// Parse the config line by line
-	lines := strings.Split(string(data), "\n")
-	for _, line := range lines {
-		// Skip comments and empty lines
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+		changed := 0
+		for _, key := range keys {
+			k, _ := config.Lookup(key)
+			current, set := f.Get(key)
+			switch {
+			case k.Default == "" && set:
+				f.Unset(key)
+				fmt.Printf("Removed '%s'\n", key)
+			case k.Default != "" && (!set || current != k.Default):
+				if err := f.Set(key, k.Default); err != nil {
+					return err
+				}
+				fmt.Printf("Reset '%s' to '%s'\n", key, k.Default)
+			default:
+				continue
+			}
+			changed++
 		}
 
-		// Split by = sign
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) != 2 {
-			continue
+		if changed == 0 {
+			fmt.Println("Configuration already matches the defaults.")
+			return nil
 		}
+		if err := f.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	},
+}
 
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		config[key] = value
-	}
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show how the configuration differs from the defaults",
+	Long: "Compare the settable keys in litetable.conf with the values 'litetable service init' " +
+		"writes. Lines starting with - are defaults, lines starting with + the current values.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		f, err := loadInstalledConfig()
+		if err != nil {
+			return err
+		}
 
-	return config, nil
+		changed := false
+		for _, key := range allowedConfigurations {
+			k, _ := config.Lookup(key)
+			current, set := f.Get(key)
+			if (set && current == k.Default) || (!set && k.Default == "") {
+				continue
+			}
+
+			changed = true
+			if k.Default != "" {
+				fmt.Printf("-%s = %s\n", key, k.Default)
+			}
+			if set {
+				fmt.Printf("+%s = %s\n", key, current)
+			}
+		}
+
+		if !changed {
+			fmt.Fprintln(os.Stderr, "Configuration matches the defaults.")
+		}
+		return nil
+	},
 }
 
-// saveConfig writes the configuration to the config file
-func saveConfig(config Config) error {
-	configPath, err := getConfigFilePath()
-	if err != nil {
-		return err
+// validateConfigInput checks the key and value flags shared by set and update
+func validateConfigInput() error {
+	if cfgKey == "" {
+		return exitcode.UsageError(fmt.Errorf("key is required"))
+	}
+	if cfgVal == "" {
+		return exitcode.UsageError(fmt.Errorf("value is required"))
 	}
 
-	// Read existing file to preserve comments
-	var lines []string
-	existingConfig := make(map[string]bool)
-	fileExists := false
-
-	if _, err := os.Stat(configPath); !os.IsNotExist(err) {
-		fileExists = true
-		fileData, err := os.ReadFile(configPath)
-		if err == nil {
-			fileLines := strings.Split(string(fileData), "\n")
-			for _, line := range fileLines {
-				// Skip empty lines at the end of file
-				if len(lines) > 0 && line == "" && len(fileLines) > 1 {
-					continue
-				}
+	if err := validateConfigKey(cfgKey); err != nil {
+		return err
+	}
 
-				trimmedLine := strings.TrimSpace(line)
-				if trimmedLine == "" || strings.HasPrefix(trimmedLine, "#") {
-					// Keep comments and empty lines
-					lines = append(lines, line)
-				} else {
-					// Extract key from existing config lines
-					parts := strings.SplitN(trimmedLine, "=", 2)
-					if len(parts) == 2 {
-						key := strings.TrimSpace(parts[0])
-						existingConfig[key] = true
-
-						// If key exists in the new config, update its value
-						if val, ok := config[key]; ok {
-							lines = append(lines, fmt.Sprintf("%s = %s", key, val))
-							delete(config, key) // Remove from config to avoid duplication
-						} else {
-							lines = append(lines, line) // Keep line as is
-						}
-					} else {
-						lines = append(lines, line) // Keep line as is
-					}
-				}
-			}
-		}
+	k, _ := config.Lookup(cfgKey)
+	if err := k.Validate(cfgVal); err != nil {
+		return exitcode.UsageError(err)
 	}
+	return nil
+}
 
-	// Append any new keys with proper formatting
-	if len(config) > 0 {
-		// Add a blank line between existing content and new entries if file exists and doesn't end with blank line
-		if fileExists && len(lines) > 0 && lines[len(lines)-1] != "" {
-			lines = append(lines, "")
-		}
+// validateConfigKey checks that key can be changed with 'litetable config'
+func validateConfigKey(key string) error {
+	if !isAllowedConfigKey(key) {
+		return exitcode.UsageError(fmt.Errorf("'%s' is not an allowed configuration key. Allowed keys: %s",
+			key, strings.Join(allowedConfigurations, ", ")))
+	}
+	return nil
+}
 
-		// Add new configurations
-		for key, value := range config {
-			if !existingConfig[key] {
-				lines = append(lines, fmt.Sprintf("%s = %s", key, value))
-			}
-		}
+// loadInstalledConfig reads litetable.conf, which must exist
+func loadInstalledConfig() (*config.File, error) {
+	f, err := config.Load()
+	if errors.Is(err, config.ErrNotInstalled) {
+		return nil, exitcode.NotFoundError(fmt.Errorf("configuration file does not exist"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	return f, nil
+}
 
-	// Ensure we end with a newline
-	output := strings.Join(lines, "\n")
-	if !strings.HasSuffix(output, "\n") && len(output) > 0 {
-		output += "\n"
+// loadConfigFile reads litetable.conf. A missing file is treated as empty, so 'set'
+// creates it.
+func loadConfigFile() (*config.File, error) {
+	configPath, err := config.Path()
+	if err != nil {
+		return nil, err
 	}
 
-	return os.WriteFile(configPath, []byte(output), 0644)
+	f, err := config.LoadFile(configPath)
+	if errors.Is(err, config.ErrNotInstalled) {
+		return config.Parse(configPath, nil), nil
+	}
+	return f, err
 }
 
 // isAllowedConfigKey checks if the provided key is in the allowed configurations list
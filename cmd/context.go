@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+)
+
+var (
+	ctxAddress  string
+	ctxRPCPort  string
+	ctxHTTPPort string
+	ctxTLS      profile.TLS
+	ctxUse      bool
+	ctxForce    bool
+)
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+	contextCmd.AddCommand(contextAddCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextRemoveCmd)
+	contextCmd.AddCommand(contextCurrentCmd)
+
+	contextAddCmd.Flags().StringVarP(&ctxAddress, "address", "a", "", "Server address (required)")
+	contextAddCmd.Flags().StringVarP(&ctxRPCPort, "rpc-port", "r", profile.DefaultRPCPort(), "Server gRPC port")
+	contextAddCmd.Flags().StringVarP(&ctxHTTPPort, "http-port", "p", profile.DefaultHTTPPort(),
+		"Server HTTP port used for health checks")
+	contextAddCmd.Flags().BoolVar(&ctxTLS.Enabled, "tls", false, "Connect using TLS")
+	contextAddCmd.Flags().StringVar(&ctxTLS.CAFile, "tls-ca-file", "",
+		"CA bundle used to verify the server (implies --tls)")
+	contextAddCmd.Flags().StringVar(&ctxTLS.CertFile, "tls-cert-file", "",
+		"Client certificate for mTLS (implies --tls)")
+	contextAddCmd.Flags().StringVar(&ctxTLS.KeyFile, "tls-key-file", "", "Client key for mTLS")
+	contextAddCmd.Flags().StringVar(&ctxTLS.ServerName, "tls-server-name", "",
+		"Override the server name used to verify the certificate")
+	contextAddCmd.Flags().BoolVar(&ctxTLS.InsecureSkipVerify, "tls-insecure-skip-verify", false,
+		"Skip server certificate verification (development only)")
+	contextAddCmd.Flags().BoolVar(&ctxUse, "use", false, "Switch to the context after adding it")
+	contextAddCmd.Flags().BoolVarP(&ctxForce, "force", "f", false, "Replace an existing context")
+	_ = contextAddCmd.MarkFlagRequired("address")
+}
+
+// contextCmd represents the context command
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Manage connection contexts",
+	Long: `Contexts are named connections to LiteTable servers, stored in ~/.litetable/contexts.json.
+The built-in "local" context uses the server configured in litetable.conf. Any command can
+target another context with --context, or another server with --server host[:rpc_port].`,
+}
+
+// contextAddCmd represents the context add command
+var contextAddCmd = &cobra.Command{
+	Use:     "add <name>",
+	Short:   "Add a connection context",
+	Example: "litetable context add staging --address staging.internal --tls-ca-file ca.pem --use",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if name == profile.Local {
+			return exitcode.UsageError(fmt.Errorf("%q is reserved for the server in litetable.conf", name))
+		}
+
+		c := &profile.Context{
+			Address:  ctxAddress,
+			RPCPort:  ctxRPCPort,
+			HTTPPort: ctxHTTPPort,
+			TLS:      ctxTLS,
+		}
+		if c.TLS.CAFile != "" || c.TLS.CertFile != "" {
+			c.TLS.Enabled = true
+		}
+		if err := c.Validate(); err != nil {
+			return exitcode.UsageError(err)
+		}
+
+		cfg, err := profile.Load()
+		if err != nil {
+			return err
+		}
+
+		if _, exists := cfg.Contexts[name]; exists && !ctxForce {
+			return exitcode.UsageError(fmt.Errorf("context '%s' already exists. Use --force to replace it", name))
+		}
+
+		cfg.Contexts[name] = c
+		if ctxUse {
+			cfg.Current = name
+		}
+
+		if err := profile.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save context: %w", err)
+		}
+
+		fmt.Printf("Added context '%s' (%s:%s)\n", name, c.Address, c.RPCPort)
+		if ctxUse {
+			fmt.Printf("Switched to context '%s'\n", name)
+		}
+		return nil
+	},
+}
+
+// contextUseCmd represents the context use command
+var contextUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch the current connection context",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		cfg, err := profile.Load()
+		if err != nil {
+			return err
+		}
+
+		if name == profile.Local {
+			cfg.Current = ""
+		} else {
+			if _, exists := cfg.Contexts[name]; !exists {
+				return exitcode.NotFoundError(fmt.Errorf("context '%s' does not exist", name))
+			}
+			cfg.Current = name
+		}
+
+		if err := profile.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save context: %w", err)
+		}
+
+		fmt.Printf("Switched to context '%s'\n", name)
+		return nil
+	},
+}
+
+// contextListCmd represents the context list command
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List connection contexts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := profile.Load()
+		if err != nil {
+			return err
+		}
+
+		current := cfg.Current
+		if current == "" {
+			current = profile.Local
+		}
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CURRENT\tNAME\tADDRESS\tRPC PORT\tHTTP PORT\tTLS")
+
+		marker := func(name string) string {
+			if name == current {
+				return "*"
+			}
+			return ""
+		}
+
+		// The local context is read from litetable.conf and may not be configured yet
+		if local, err := profile.LocalTarget(); err == nil {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", marker(profile.Local), profile.Local,
+				local.Address, local.RPCPort, local.HTTPPort, local.TLS.Enabled)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", marker(profile.Local), profile.Local,
+				"(not configured)", "", "", "")
+		}
+
+		for _, name := range cfg.Names() {
+			c := cfg.Contexts[name]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", marker(name), name,
+				c.Address, c.RPCPort, c.HTTPPort, c.TLS.Enabled)
+		}
+
+		return tw.Flush()
+	},
+}
+
+// contextRemoveCmd represents the context remove command
+var contextRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a connection context",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if name == profile.Local {
+			return exitcode.UsageError(fmt.Errorf("the %q context cannot be removed", name))
+		}
+
+		cfg, err := profile.Load()
+		if err != nil {
+			return err
+		}
+
+		if _, exists := cfg.Contexts[name]; !exists {
+			return exitcode.NotFoundError(fmt.Errorf("context '%s' does not exist", name))
+		}
+
+		delete(cfg.Contexts, name)
+		if cfg.Current == name {
+			cfg.Current = ""
+			fmt.Printf("Context '%s' was current; switched to '%s'\n", name, profile.Local)
+		}
+
+		if err := profile.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save context: %w", err)
+		}
+
+		fmt.Printf("Removed context '%s'\n", name)
+		return nil
+	},
+}
+
+// contextCurrentCmd represents the context current command
+var contextCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the context commands will use",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		target, err := profile.Resolve()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s (%s)\n", target.Name, target.RPCAddress())
+		return nil
+	},
+}
@@ -0,0 +1,312 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	litetable2 "github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/server"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultTopK = 5
+	maxTopK     = 50
+)
+
+// errFamiliesUnknown is returned when a question does not name the families to search and
+// the server is not the local one
+var errFamiliesUnknown = errors.New("the families of a remote server cannot be listed; " +
+	"pass them as \"families\" in the request")
+
+// stopWords are ignored when scoring rows against a question
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "were": true,
+	"what": true, "which": true, "who": true, "whom": true, "how": true, "why": true,
+	"when": true, "where": true, "does": true, "did": true, "that": true, "this": true,
+	"with": true, "from": true, "about": true, "any": true, "all": true, "have": true,
+	"has": true, "can": true, "you": true, "tell": true, "me": true, "is": true,
+}
+
+type completionRequest struct {
+	Question  string    `json:"question"`
+	TopK      int       `json:"top_k"`
+	Threshold float64   `json:"threshold"`
+	LLM       llmConfig `json:"llm"`
+	// Families to search; required for servers other than the local one, whose families
+	// cannot be listed
+	Families []string `json:"families"`
+}
+
+// completionChunk is one line of the newline-delimited JSON response
+type completionChunk struct {
+	Response string `json:"response"`
+	Error    string `json:"error,omitempty"`
+}
+
+// completionMatch is a row retrieved as context for a question
+type completionMatch struct {
+	family string
+	row    *litetable2.Row
+	score  float64
+}
+
+// describe renders the row on a single line for prompts and answers
+func (m *completionMatch) describe() string {
+	qualifiers := m.row.Columns[m.family]
+	names := make([]string, 0, len(qualifiers))
+	for name := range qualifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		values := qualifiers[name]
+		if len(values) == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", name, printable(values[0].Value)))
+	}
+
+	return fmt.Sprintf("%s [%s]: %s", m.row.Key, m.family, strings.Join(parts, ", "))
+}
+
+// completionPrompt is what an llm backend receives
+type completionPrompt struct {
+	System  string
+	User    string
+	Matches []completionMatch
+}
+
+// completions answers a question about the stored data, streaming newline-delimited
+// JSON chunks of the form {"response": "..."}
+func (h *handler) completions(w http.ResponseWriter, r *http.Request) {
+	var req completionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("Failed to decode JSON payload: %v", err),
+		})
+		return
+	}
+
+	req.Question = strings.TrimSpace(req.Question)
+	if req.Question == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": "Question must be specified",
+		})
+		return
+	}
+
+	backend, err := newLLM(req.LLM)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("%v", err),
+		})
+		return
+	}
+
+	matches, err := h.retrieve(r.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errFamiliesUnknown) {
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("Failed to retrieve rows: %v", err),
+		})
+		return
+	}
+
+	// Answers can take longer than the server's write timeout, so lift it for this stream
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	emit := func(chunk string) error {
+		if err := enc.Encode(completionChunk{Response: chunk}); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	// The status is already sent once streaming starts, so failures are reported in-band
+	if err := backend.Stream(r.Context(), buildPrompt(req.Question, matches), emit); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		_ = enc.Encode(completionChunk{
+			Response: fmt.Sprintf("\n\nError: %v", err),
+			Error:    err.Error(),
+		})
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// retrieve scores every row of the requested families, or every family of the local
+// server, against the question and returns the top_k rows whose score reaches the
+// threshold, best first.
+func (h *handler) retrieve(ctx context.Context, req *completionRequest) ([]completionMatch, error) {
+	families := req.Families
+	if len(families) == 0 {
+		if !h.localServer {
+			return nil, errFamiliesUnknown
+		}
+		var err error
+		if families, err = dir.GetFamilies(); err != nil {
+			// Without a families file there is nothing to search
+			return nil, nil
+		}
+	}
+
+	terms := questionTerms(req.Question)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var matches []completionMatch
+	for _, family := range families {
+		rows, err := h.server.Read(ctx, &server.ReadParams{
+			Key:       ".*",
+			QueryType: server.ReadRegex,
+			Family:    family,
+			Latest:    1,
+		})
+		if err != nil {
+			if errors.Is(err, server.ErrRowNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read family %s: %w", family, err)
+		}
+
+		for _, row := range rows {
+			score := scoreRow(terms, family, row)
+			if score > 0 && score >= req.Threshold {
+				matches = append(matches, completionMatch{family: family, row: row, score: score})
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].row.Key < matches[j].row.Key
+	})
+
+	topK := req.TopK
+	if topK <= 0 {
+		topK = defaultTopK
+	}
+	if topK > maxTopK {
+		topK = maxTopK
+	}
+	if len(matches) > topK {
+		matches = matches[:topK]
+	}
+
+	return matches, nil
+}
+
+// questionTerms splits a question into lower-case search terms, dropping stop words
+func questionTerms(question string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range tokenize(question) {
+		if len(word) < 2 || stopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// scoreRow returns the fraction of question terms found in the row key, qualifier names
+// or values of the family.
+func scoreRow(terms []string, family string, row *litetable2.Row) float64 {
+	words := make(map[string]bool)
+	add := func(s string) {
+		for _, w := range tokenize(s) {
+			words[w] = true
+		}
+	}
+
+	add(row.Key)
+	add(family)
+	for name, values := range row.Columns[family] {
+		add(name)
+		for _, v := range values {
+			if utf8.Valid(v.Value) {
+				add(v.GetString())
+			}
+		}
+	}
+
+	found := 0
+	for _, t := range terms {
+		if words[t] {
+			found++
+		}
+	}
+	return float64(found) / float64(len(terms))
+}
+
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// buildPrompt assembles the instructions and retrieved rows for the backend
+func buildPrompt(question string, matches []completionMatch) *completionPrompt {
+	var user strings.Builder
+	if len(matches) == 0 {
+		user.WriteString("No rows in the database matched the question.\n")
+	} else {
+		user.WriteString("Rows from the database (row key [family]: qualifier=value):\n")
+		for _, m := range matches {
+			fmt.Fprintf(&user, "- %s\n", m.describe())
+		}
+	}
+	fmt.Fprintf(&user, "\nQuestion: %s\n", question)
+
+	return &completionPrompt{
+		System: "You answer questions about data stored in a LiteTable wide-column database. " +
+			"Use only the rows provided. If they do not contain the answer, say so.",
+		User:    user.String(),
+		Matches: matches,
+	}
+}
+
+// printable returns the value as text, or a short placeholder for binary data
+func printable(b []byte) string {
+	if !utf8.Valid(b) {
+		return fmt.Sprintf("<%d bytes of binary data>", len(b))
+	}
+	return string(b)
+}
@@ -1,15 +1,26 @@
 package dashboard
 
 import (
+	"context"
 	"embed"
+	"errors"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
 	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
 	"io/fs"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"runtime"
+	"strconv"
+	"syscall"
+	"time"
 )
 
 //go:embed web/*
@@ -17,32 +28,64 @@ var webContent embed.FS
 
 const (
 	dashboardHost = "127.0.0.1"
-	dashboardPort = "7654"
+	dashboardPort = 7654
+
+	shutdownTimeout = 10 * time.Second
 )
 
 var (
+	host      string
+	port      int
+	noBrowser bool
+	ollamaURL string
+
 	Command = &cobra.Command{
 		Use:   "dashboard",
 		Short: "Open LiteTable dashboard in a browser",
-		Long:  "Opens a browser window with the LiteTable dashboard interface",
-		Run: func(cmd *cobra.Command, args []string) {
-			startDashboard()
+		Long: "Opens a browser window with the LiteTable dashboard interface. The dashboard " +
+			"runs until it receives an interrupt or termination signal.",
+		Example: "litetable dashboard\n\nlitetable dashboard --host 0.0.0.0 --port 8080 --no-browser",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if port < 0 || port > 65535 {
+				return fmt.Errorf("port must be between 0 and 65535")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return startDashboard(ctx)
 		},
 	}
 )
 
-func startDashboard() {
+func init() {
+	Command.Flags().StringVar(&host, "host", dashboardHost, "Address to bind the dashboard to")
+	Command.Flags().IntVarP(&port, "port", "p", dashboardPort,
+		"Port to serve the dashboard on (0 picks a free port)")
+	Command.Flags().BoolVar(&noBrowser, "no-browser", false, "Do not open a browser window")
+	Command.Flags().StringVar(&ollamaURL, "ollama-url", "",
+		"Ollama server the chat panels use (default: OLLAMA_HOST or "+defaultOllamaURL+")")
+}
+
+// startDashboard serves the dashboard until ctx is cancelled, then shuts the server down
+// gracefully and closes the LiteTable client.
+func startDashboard(ctx context.Context) error {
 	// Get web content from embedded files
 	webFS, err := fs.Sub(webContent, "web")
 	if err != nil {
-		fmt.Printf("Failed to load web content: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to load web content: %w", err)
+	}
+
+	target, err := profile.Resolve()
+	if err != nil {
+		return exitcode.ConnectionError(fmt.Errorf("failed to create LiteTable client: %w", err))
 	}
 
-	litetableClient, err := server.NewClient()
+	litetableClient, err := server.NewClientForTarget(target)
 	if err != nil {
-		fmt.Printf("Failed to create LiteTable client: %v\n", err)
-		os.Exit(1)
+		return exitcode.ConnectionError(fmt.Errorf("failed to create LiteTable client: %w", err))
 	}
 
 	defer func() {
@@ -50,34 +93,95 @@ func startDashboard() {
 	}()
 
 	ltHandler := &handler{
-		server: litetableClient,
+		server:      litetableClient,
+		localServer: target.IsLocal(),
 	}
 
+	// Bind before serving so address errors are reported immediately and port 0 resolves
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("failed to start dashboard: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           routes(webFS, ltHandler),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      2 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+
+	url := fmt.Sprintf("http://%s", listener.Addr().String())
+	fmt.Printf("Starting dashboard server at %s\n", url)
+
+	if !noBrowser {
+		fmt.Printf("Opening browser at %s\n", url)
+		openBrowser(url)
+	}
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("dashboard server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Println("\nShutting down dashboard...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		_ = srv.Close()
+		return fmt.Errorf("failed to shut down dashboard: %w", err)
+	}
+
+	fmt.Println("Dashboard stopped.")
+	return nil
+}
+
+// routes registers the static files and API handlers on a dedicated mux
+func routes(webFS fs.FS, h *handler) http.Handler {
+	mux := http.NewServeMux()
+
 	// Serve static files
-	http.Handle("/", http.FileServer(http.FS(webFS)))
+	mux.Handle("/", http.FileServer(http.FS(webFS)))
 
 	// Serve handlers
-	http.Handle("POST /query", http.HandlerFunc(ltHandler.query))
-	http.Handle("GET /families", http.HandlerFunc(ltHandler.getFamilies))
+	mux.Handle("POST /query", sameOriginJSON(http.HandlerFunc(h.query)))
+	mux.Handle("GET /families", http.HandlerFunc(h.getFamilies))
+	mux.Handle("POST /completions", sameOriginJSON(http.HandlerFunc(h.completions)))
 
-	addr := fmt.Sprintf("%s:%s", dashboardHost, dashboardPort)
+	return mux
+}
 
-	// Start the server in a goroutine
-	go func() {
-		// Start the HTTP server
-		fmt.Printf("Starting dashboard server at http://%s\n", addr)
-		if err := http.ListenAndServe(addr, nil); err != nil {
-			fmt.Printf("Failed to start dashboard: %v\n", err)
-			os.Exit(1)
+// sameOriginJSON rejects requests that are not application/json or that come from a page
+// on another origin. Browsers send form posts and other "simple" cross-site requests
+// without asking first, so without this any page could use the dashboard's API.
+func sameOriginJSON(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+			return
 		}
-	}()
 
-	// Open browser
-	fmt.Printf("Opening browser at %s\n", addr)
-	openBrowser(fmt.Sprintf("http://%s", addr))
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				http.Error(w, "cross-origin requests are not allowed", http.StatusForbidden)
+				return
+			}
+		}
 
-	// Keep the server running
-	select {}
+		next.ServeHTTP(w, r)
+	})
 }
 
 func openBrowser(url string) {
@@ -96,7 +200,7 @@ func openBrowser(url string) {
 	}
 
 	if err != nil {
-		fmt.Printf("Failed to open browser: %v\n", err)
-		fmt.Printf("Please open your browser manually at: %s\n", url)
+		fmt.Fprintf(os.Stderr, "Failed to open browser: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Please open your browser manually at: %s\n", url)
 	}
 }
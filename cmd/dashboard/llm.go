@@ -0,0 +1,190 @@
+package dashboard
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	llmOllama = "ollama"
+	llmLocal  = "local"
+
+	defaultOllamaURL   = "http://127.0.0.1:11434"
+	defaultOllamaModel = "llama3.2:latest"
+)
+
+// llmConfig is the "llm" block of a completions request. The backend's address is not
+// part of it: the dashboard only talks to the Ollama server set with --ollama-url or
+// OLLAMA_HOST, so a page cannot make it send requests to arbitrary URLs.
+type llmConfig struct {
+	Type  string `json:"type"`
+	Model string `json:"model"`
+}
+
+// llm streams a completion for a prompt, calling emit for every chunk of the response
+type llm interface {
+	Stream(ctx context.Context, prompt *completionPrompt, emit func(chunk string) error) error
+}
+
+// llmBackends holds the constructors for every supported backend, keyed by type
+var llmBackends = map[string]func(cfg llmConfig) (llm, error){
+	llmOllama: newOllamaLLM,
+	llmLocal:  newLocalLLM,
+}
+
+// newLLM creates the backend requested by cfg
+func newLLM(cfg llmConfig) (llm, error) {
+	if cfg.Type == "" {
+		cfg.Type = llmOllama
+	}
+
+	create, ok := llmBackends[cfg.Type]
+	if !ok {
+		names := make([]string, 0, len(llmBackends))
+		for name := range llmBackends {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unsupported llm type %q (supported: %s)", cfg.Type,
+			strings.Join(names, ", "))
+	}
+
+	return create(cfg)
+}
+
+// ollamaLLM streams completions from an Ollama server
+type ollamaLLM struct {
+	url    string
+	model  string
+	client *http.Client
+}
+
+func newOllamaLLM(cfg llmConfig) (llm, error) {
+	url := ollamaURL
+	if url == "" {
+		url = os.Getenv("OLLAMA_HOST")
+	}
+	if url == "" {
+		url = defaultOllamaURL
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = "http://" + url
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	return &ollamaLLM{
+		url:    strings.TrimSuffix(url, "/"),
+		model:  model,
+		client: &http.Client{},
+	}, nil
+}
+
+func (o *ollamaLLM) Stream(ctx context.Context, prompt *completionPrompt,
+	emit func(chunk string) error) error {
+	body, err := json.Marshal(map[string]any{
+		"model":  o.model,
+		"system": prompt.System,
+		"prompt": prompt.User,
+		"stream": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.url+"/api/generate",
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach ollama at %s: %w", o.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&msg)
+		return fmt.Errorf("ollama returned status %d: %s", resp.StatusCode, msg.Error)
+	}
+
+	// Ollama streams one JSON object per line
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var chunk struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+			Error    string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return fmt.Errorf("failed to decode ollama response: %w", err)
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama: %s", chunk.Error)
+		}
+		if chunk.Response != "" {
+			if err := emit(chunk.Response); err != nil {
+				return err
+			}
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+
+	return scanner.Err()
+}
+
+// localLLM is a stand-in backend that needs no model. It answers by listing the rows
+// retrieved for the question, which makes the completions flow testable offline.
+type localLLM struct{}
+
+func newLocalLLM(llmConfig) (llm, error) {
+	return &localLLM{}, nil
+}
+
+func (l *localLLM) Stream(ctx context.Context, prompt *completionPrompt,
+	emit func(chunk string) error) error {
+	var answer strings.Builder
+	if len(prompt.Matches) == 0 {
+		answer.WriteString("I could not find any rows related to your question.")
+	} else {
+		noun := "rows"
+		if len(prompt.Matches) == 1 {
+			noun = "row"
+		}
+		fmt.Fprintf(&answer, "I found %d %s related to your question:\n", len(prompt.Matches), noun)
+		for _, m := range prompt.Matches {
+			fmt.Fprintf(&answer, "\n- %s (score %.2f)", m.describe(), m.score)
+		}
+	}
+
+	// Stream word by word to exercise the same path as a real model
+	words := strings.SplitAfter(answer.String(), " ")
+	for _, w := range words {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
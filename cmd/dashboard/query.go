@@ -36,6 +36,9 @@ type payload struct {
 
 type handler struct {
 	server litetable
+	// localServer is set when the dashboard talks to the locally installed server, whose
+	// families are listed in the families file
+	localServer bool
 }
 
 // query handles LiteTable query requests
@@ -45,6 +48,8 @@ func (h *handler) query(w http.ResponseWriter, r *http.Request) {
 	// Decode the JSON payload
 	var p payload
 	decoder := json.NewDecoder(r.Body)
+	// Keep numbers exactly as they were sent instead of converting them to float64
+	decoder.UseNumber()
 	if err := decoder.Decode(&p); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(map[string]string{
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/cmd/service"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/doctor"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/litetable/litetable-cli/internal/server"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the LiteTable setup",
+	Long: "Checks the tools needed to build the server, the ~/.litetable layout, litetable.conf, " +
+		"the PATH set up by install.sh, the server process and its ports, whether the server is " +
+		"reachable, compatibility with the CLI and free disk space, and prints a fix for every " +
+		"problem found.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("🩺 Checking your LiteTable setup...")
+		fmt.Println()
+
+		checks := []doctor.Check{checkConfig, checkPath}
+		checks = append(checks, service.DoctorChecks()...)
+		checks = append(checks, checkCompatibility)
+
+		failures := doctor.Run(os.Stdout, checks)
+		fmt.Println()
+		if failures > 0 {
+			return exitcode.New(exitcode.General, fmt.Errorf("doctor found %d problem(s)", failures))
+		}
+		fmt.Println("No problems found.")
+		return nil
+	},
+}
+
+// checkConfig parses litetable.conf and reports malformed lines, duplicate, unknown and
+// missing keys, values that do not parse and TLS files that do not exist
+func checkConfig() []doctor.Result {
+	name := "config"
+	configPath, err := config.Path()
+	if err != nil {
+		return []doctor.Result{doctor.Fail(name, err.Error(), "make sure $HOME is set")}
+	}
+
+	f, err := config.LoadFile(configPath)
+	if errors.Is(err, config.ErrNotInstalled) {
+		return []doctor.Result{doctor.Fail(name, fmt.Sprintf("%s does not exist", configPath),
+			"run 'litetable service init' to create it")}
+	}
+	if err != nil {
+		return []doctor.Result{doctor.Fail(name, err.Error(),
+			fmt.Sprintf("make %s readable by your user", configPath))}
+	}
+
+	var results []doctor.Result
+	for _, issue := range f.Issues() {
+		switch issue.Kind {
+		case config.IssueMalformed:
+			results = append(results, doctor.Fail(name, fmt.Sprintf("%s: %v", configPath, issue),
+				"fix or remove the line"))
+		case config.IssueUnknown:
+			results = append(results, doctor.Warn(name, issue.Error(),
+				fmt.Sprintf("check the spelling or remove it; settable keys: %s",
+					strings.Join(allowedConfigurations, ", "))))
+		case config.IssueDuplicate:
+			results = append(results, doctor.Warn(name, issue.Error(), "remove the duplicate line"))
+		case config.IssueInvalid:
+			results = append(results, doctor.Fail(name, issue.Error(),
+				fmt.Sprintf("correct it with 'litetable config update -k %s -v <value>'", issue.Key)))
+		case config.IssueMissing:
+			results = append(results, doctor.Fail(name, issue.Error(),
+				"run 'litetable service init --force' to write a complete configuration"))
+		}
+	}
+
+	for _, key := range []string{config.TLSCAFile, config.TLSCertFile, config.TLSKeyFile} {
+		if value, ok := f.Get(key); ok && value != "" {
+			if _, err := os.Stat(value); err != nil {
+				results = append(results, doctor.Fail(name, fmt.Sprintf("%s = %s does not exist", key, value),
+					fmt.Sprintf("correct it with 'litetable config update -k %s -v <path>'", key)))
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, doctor.OK(name, "%s is valid", configPath))
+	}
+	return results
+}
+
+// checkPath checks the PATH entry install.sh adds to the shell profile, and that the
+// litetable on PATH is the installed one
+func checkPath() []doctor.Result {
+	name := "PATH"
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return nil
+	}
+	binDir := filepath.Join(liteTableDir, "bin")
+	pathLine := fmt.Sprintf(`export PATH="%s:$PATH"`, binDir)
+
+	// install.sh writes to the first of these that exists
+	home := filepath.Dir(liteTableDir)
+	var shellFile string
+	for _, rc := range []string{".zshrc", ".bashrc", ".profile"} {
+		data, err := os.ReadFile(filepath.Join(home, rc))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == pathLine {
+				shellFile = filepath.Join(home, rc)
+				break
+			}
+		}
+		if shellFile != "" {
+			break
+		}
+	}
+
+	var results []doctor.Result
+	if shellFile == "" {
+		results = append(results, doctor.Warn(name,
+			"no PATH entry for ~/.litetable/bin in ~/.zshrc, ~/.bashrc or ~/.profile",
+			fmt.Sprintf("add '%s' to your shell profile, or rerun install.sh", pathLine)))
+	}
+
+	onPath := false
+	for _, p := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(p) == binDir {
+			onPath = true
+			break
+		}
+	}
+	if !onPath {
+		fix := "restart your terminal"
+		if shellFile != "" {
+			fix = fmt.Sprintf("run 'source %s' or restart your terminal", shellFile)
+		}
+		results = append(results, doctor.Warn(name, fmt.Sprintf("%s is not on PATH in this shell", binDir), fix))
+	}
+
+	installed := filepath.Join(binDir, "litetable")
+	if found, err := exec.LookPath("litetable"); err == nil && onPath {
+		if resolved, _ := filepath.Abs(found); resolved != installed {
+			if _, err := os.Stat(installed); err == nil {
+				results = append(results, doctor.Warn(name,
+					fmt.Sprintf("litetable resolves to %s instead of %s", found, installed),
+					fmt.Sprintf("remove %s or move %s earlier in PATH", found, binDir)))
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		results = append(results, doctor.OK(name, "%s is on PATH (set in %s)", binDir, shellFile))
+	}
+	return results
+}
+
+// checkCompatibility reports whether the server of the active context is a release tested
+// with the CLI's proto package. Servers of unknown or untested versions are a warning, not
+// a failure, as nothing says they are incompatible.
+func checkCompatibility() []doctor.Result {
+	name := "compatibility"
+	cli := fmt.Sprintf("CLI %s (proto %s)", litetable.CLIVersion, server.ProtoVersion())
+
+	target, err := profile.Resolve()
+	if err != nil {
+		return []doctor.Result{doctor.Warn(name, fmt.Sprintf("%s; cannot determine the server: %v", cli, err),
+			"run 'litetable service init' or select a context with 'litetable context use'")}
+	}
+
+	compat := server.CheckCompatibility(target.ServerVersion)
+	if !compat.Tested {
+		detail := fmt.Sprintf("%s; compatibility with server %s is %s: its version is not known",
+			cli, target.Name, compat.Status())
+		if target.ServerVersion != "" {
+			detail = fmt.Sprintf("%s; compatibility with server %s is %s: it is not a tested release",
+				cli, target.ServerVersion, compat.Status())
+		}
+		return []doctor.Result{doctor.Warn(name, detail,
+			"check the server's release notes for the litetable-db/pkg version it was built with")}
+	}
+
+	return []doctor.Result{doctor.OK(name, "%s is %s with server %s", cli, compat.Status(), compat.ServerVersion)}
+}
@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
 	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
 	"strings"
@@ -18,8 +19,8 @@ var (
 		Short:   "Create configuration in the Litetable server",
 		Long:    "Create configuration elements such as column families in the Litetable server",
 		Example: "litetable create -f 'family1, family2, family3'",
-		Run: func(cmd *cobra.Command, args []string) {
-			createFamilies()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return createFamilies()
 		},
 	}
 )
@@ -30,7 +31,7 @@ func init() {
 	_ = CreateCmd.MarkFlagRequired("family")
 }
 
-func createFamilies() {
+func createFamilies() error {
 	start := time.Now()
 
 	var familyParams server.CreateFamilyParams
@@ -47,15 +48,13 @@ func createFamilies() {
 
 	// Check if we have any families to create
 	if len(familyParams.Families) == 0 {
-		fmt.Printf("no valid family names provided")
-		return
+		return exitcode.UsageError(fmt.Errorf("no valid family names provided"))
 	}
 
 	// Create a new gRPC client
 	client, err := server.NewClient()
 	if err != nil {
-		fmt.Printf("%v", err)
-		return
+		return exitcode.ConnectionError(err)
 	}
 
 	defer func() {
@@ -64,9 +63,9 @@ func createFamilies() {
 
 	// Create families on the server
 	if err = client.CreateFamilies(context.Background(), &familyParams); err != nil {
-		fmt.Printf("%w", err)
-		return
+		return fmt.Errorf("failed to create families: %w", err)
 	}
 
 	fmt.Printf("Created famililes in %s\n", time.Since(start))
+	return nil
 }
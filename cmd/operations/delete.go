@@ -3,6 +3,7 @@ package operations
 import (
 	"context"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
 	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
 	"time"
@@ -20,9 +21,9 @@ var (
 		Use:   "delete",
 		Short: "Delete data from the Litetable server",
 		Long:  "Delete allows you to remove data from the Litetable server",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// Execute the delete operation
-			deleteData()
+			return deleteData()
 		},
 	}
 )
@@ -39,7 +40,7 @@ func init() {
 	_ = DeleteCmd.MarkFlagRequired("key")
 }
 
-func deleteData() {
+func deleteData() error {
 	now := time.Now()
 
 	var qualifiers []string
@@ -62,8 +63,7 @@ func deleteData() {
 
 	client, err := server.NewClient()
 	if err != nil {
-		fmt.Printf("%v", err)
-		return
+		return exitcode.ConnectionError(err)
 	}
 
 	defer func(client *server.GrpcClient) {
@@ -71,9 +71,9 @@ func deleteData() {
 	}(client)
 
 	if err = client.Delete(context.Background(), opts); err != nil {
-		fmt.Printf("%v", err)
-		return
+		return fmt.Errorf("failed to delete data: %w", err)
 	}
 
 	fmt.Println("Delete successful in", time.Since(now))
+	return nil
 }
@@ -0,0 +1,363 @@
+package operations
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/server"
+	"github.com/spf13/cobra"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	exportJSON   = "json"
+	exportNDJSON = "ndjson"
+	exportCSV    = "csv"
+
+	// exportEveryVersion is the number of versions read per qualifier for --all-versions
+	exportEveryVersion = math.MaxInt32
+)
+
+var (
+	exportFamilies    []string
+	exportPrefix      string
+	exportRegex       string
+	exportFile        string
+	exportFormat      string
+	exportAllVersions bool
+
+	ExportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export rows to a JSON, NDJSON or CSV file",
+		Long: "Export reads every row matching the selector and writes it in the " +
+			"{rowkey, family, qualifiers} format used by test_data/*.json, so JSON and NDJSON " +
+			"exports can be loaded again with 'litetable import'. Without --prefix or --regex " +
+			"every row in the family is exported.",
+		Example: "litetable export -f cars --out cars.json\n\n" +
+			"litetable export -f cars -f galaxies --prefix 'car:' --format ndjson > rows.ndjson",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if exportPrefix != "" && exportRegex != "" {
+				return fmt.Errorf("only one of --prefix (-p) or --regex (-r) may be provided")
+			}
+
+			// Infer the format from the file extension unless it was set explicitly
+			if !cmd.Flags().Changed("format") && exportFile != "" {
+				switch strings.ToLower(filepath.Ext(exportFile)) {
+				case ".ndjson", ".jsonl":
+					exportFormat = exportNDJSON
+				case ".csv":
+					exportFormat = exportCSV
+				}
+			}
+
+			switch exportFormat {
+			case exportJSON, exportNDJSON, exportCSV:
+				return nil
+			default:
+				return fmt.Errorf("unsupported export format %q (supported: json, ndjson, csv)",
+					exportFormat)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportData()
+		},
+	}
+)
+
+func init() {
+	ExportCmd.Flags().StringArrayVarP(&exportFamilies, "family", "f", []string{},
+		"Column family to export (can be specified multiple times)")
+	ExportCmd.Flags().StringVarP(&exportPrefix, "prefix", "p", "",
+		"Export all row-keys with this prefix")
+	ExportCmd.Flags().StringVarP(&exportRegex, "regex", "r", "",
+		"Export all row-keys matching this regex pattern")
+	ExportCmd.Flags().StringVar(&exportFile, "out", "",
+		"File to write the export to (defaults to stdout)")
+	ExportCmd.Flags().StringVar(&exportFormat, "format", exportJSON,
+		"Export format: json, ndjson or csv (inferred from the --out extension)")
+	ExportCmd.Flags().BoolVarP(&exportAllVersions, "all-versions", "a", false,
+		"Export every timestamped version instead of only the latest")
+
+	_ = ExportCmd.MarkFlagRequired("family")
+}
+
+// recordWriter streams records to the export destination
+type recordWriter interface {
+	Write(rec *litetable.Record) error
+	Close() error
+}
+
+func exportData() error {
+	start := time.Now()
+
+	client, err := server.NewClient()
+	if err != nil {
+		return exitcode.ConnectionError(err)
+	}
+
+	defer func(client *server.GrpcClient) {
+		_ = client.Close()
+	}(client)
+
+	// A file export goes to a temporary file that only replaces --out once the export
+	// succeeded, so a failed export does not leave an empty or partial file behind
+	var out io.Writer = os.Stdout
+	var tmp *os.File
+	if exportFile != "" {
+		tmp, err = os.CreateTemp(filepath.Dir(exportFile), "."+filepath.Base(exportFile)+"-*")
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer func() {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}()
+		out = tmp
+	}
+
+	bw := bufio.NewWriter(out)
+	var w recordWriter
+	switch exportFormat {
+	case exportNDJSON:
+		w = &ndjsonRecordWriter{enc: json.NewEncoder(bw)}
+	case exportCSV:
+		w = newCSVRecordWriter(bw)
+	default:
+		w = &jsonRecordWriter{w: bw}
+	}
+
+	latest := int32(1)
+	if exportAllVersions {
+		latest = exportEveryVersion
+	}
+
+	// Without a selector, match every row key
+	queryType, queryKey := server.ReadRegex, ".*"
+	if exportPrefix != "" {
+		queryType, queryKey = server.ReadPrefix, exportPrefix
+	} else if exportRegex != "" {
+		queryKey = exportRegex
+	}
+
+	var rowCount, recordCount int
+	for _, family := range exportFamilies {
+		rows, err := client.Read(context.Background(), &server.ReadParams{
+			Key:       queryKey,
+			QueryType: queryType,
+			Family:    family,
+			Latest:    latest,
+		})
+		if err != nil {
+			if errors.Is(err, server.ErrRowNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to read family %s: %w", family, err)
+		}
+
+		keys := make([]string, 0, len(rows))
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			records := exportRecords(rows[key], family)
+			for i := range records {
+				if err := w.Write(&records[i]); err != nil {
+					return fmt.Errorf("failed to write export: %w", err)
+				}
+			}
+			rowCount++
+			recordCount += len(records)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	if tmp != nil {
+		// CreateTemp makes the file private; exports are as readable as os.Create makes them
+		if err := tmp.Chmod(0644); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), exportFile); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d rows (%d records) in %s\n",
+		rowCount, recordCount, time.Since(start))
+	return nil
+}
+
+// exportRecords converts a row into fixture records. Only the latest version of each
+// qualifier is kept unless every version was requested, in which case one record per
+// version is produced, oldest first, so an import recreates the versions in order.
+func exportRecords(row *litetable.Row, family string) []litetable.Record {
+	qualifiers := row.Columns[family]
+
+	if !exportAllVersions {
+		rec := litetable.Record{
+			RowKey:     row.Key,
+			Family:     family,
+			Qualifiers: make(map[string]any, len(qualifiers)),
+		}
+		for name, values := range qualifiers {
+			if len(values) == 0 {
+				continue
+			}
+			newest := values[0]
+			for _, v := range values[1:] {
+				if v.Timestamp > newest.Timestamp {
+					newest = v
+				}
+			}
+			rec.Qualifiers[name] = litetable.JSONValue(newest.Value)
+		}
+		if len(rec.Qualifiers) == 0 {
+			return nil
+		}
+		return []litetable.Record{rec}
+	}
+
+	type version struct {
+		name  string
+		value litetable.TimestampedValue
+	}
+
+	var versions []version
+	for name, values := range qualifiers {
+		for _, v := range values {
+			versions = append(versions, version{name: name, value: v})
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].value.Timestamp != versions[j].value.Timestamp {
+			return versions[i].value.Timestamp < versions[j].value.Timestamp
+		}
+		return versions[i].name < versions[j].name
+	})
+
+	records := make([]litetable.Record, 0, len(versions))
+	for _, v := range versions {
+		records = append(records, litetable.Record{
+			RowKey:     row.Key,
+			Family:     family,
+			Qualifiers: map[string]any{v.name: litetable.JSONValue(v.value.Value)},
+			Timestamp:  v.value.Timestamp,
+		})
+	}
+	return records
+}
+
+// jsonRecordWriter writes a JSON array, one record per line
+type jsonRecordWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonRecordWriter) Write(rec *litetable.Record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	j.count++
+
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonRecordWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// ndjsonRecordWriter writes one record per line
+type ndjsonRecordWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonRecordWriter) Write(rec *litetable.Record) error {
+	return n.enc.Encode(rec)
+}
+
+func (n *ndjsonRecordWriter) Close() error {
+	return nil
+}
+
+// csvRecordWriter writes one line per qualifier
+type csvRecordWriter struct {
+	w *csv.Writer
+}
+
+func newCSVRecordWriter(w io.Writer) *csvRecordWriter {
+	cw := csv.NewWriter(w)
+	// Write errors are buffered by the csv.Writer and reported on Close
+	_ = cw.Write([]string{"rowkey", "family", "qualifier", "value", "timestamp"})
+	return &csvRecordWriter{w: cw}
+}
+
+func (c *csvRecordWriter) Write(rec *litetable.Record) error {
+	names := make([]string, 0, len(rec.Qualifiers))
+	for name := range rec.Qualifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := c.w.Write([]string{
+			rec.RowKey,
+			rec.Family,
+			name,
+			csvValue(rec.Qualifiers[name]),
+			strconv.FormatInt(rec.Timestamp, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvValue renders a record value for a CSV cell, keeping binary values base64 encoded
+func csvValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func (c *csvRecordWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
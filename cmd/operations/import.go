@@ -0,0 +1,295 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/server"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	importConcurrency int
+	importNoCreate    bool
+	importMaxErrors   int
+
+	ImportCmd = &cobra.Command{
+		Use:   "import [file]",
+		Short: "Import rows from a JSON fixture file",
+		Long: "Import loads rows in the {rowkey, family, qualifiers} format used by test_data/*.json. " +
+			"The input may be a JSON array or newline-delimited JSON, read from a file or from stdin " +
+			"when no file (or '-') is given. Missing column families are created before writing.",
+		Example: "litetable import test_data/cars.json\n\ncat rows.ndjson | litetable import --concurrency 16",
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if importConcurrency < 1 {
+				return fmt.Errorf("concurrency must be at least 1")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := "-"
+			if len(args) == 1 {
+				source = args[0]
+			}
+			return importData(source)
+		},
+	}
+)
+
+func init() {
+	ImportCmd.Flags().IntVarP(&importConcurrency, "concurrency", "c", 8,
+		"Maximum number of concurrent writes")
+	ImportCmd.Flags().BoolVar(&importNoCreate, "no-create", false,
+		"Do not create missing column families")
+	ImportCmd.Flags().IntVar(&importMaxErrors, "show-errors", 10,
+		"Maximum number of failed rows to list in the summary")
+}
+
+// importBatch is a single Write call: every qualifier for one row key in one family
+type importBatch struct {
+	key        string
+	family     string
+	qualifiers []server.Qualifier
+}
+
+// importFailure records a row that could not be written
+type importFailure struct {
+	key    string
+	family string
+	err    error
+}
+
+func importData(source string) error {
+	start := time.Now()
+
+	var in io.Reader = os.Stdin
+	if source != "-" {
+		f, err := os.Open(source)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return exitcode.NotFoundError(fmt.Errorf("import file not found: %s", source))
+			}
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	records, err := litetable.DecodeRecords(in)
+	if err != nil {
+		return exitcode.UsageError(err)
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(os.Stderr, "No records found to import.")
+		return nil
+	}
+
+	waves, families, err := buildImportBatches(records)
+	if err != nil {
+		return exitcode.UsageError(err)
+	}
+
+	total := 0
+	for _, wave := range waves {
+		total += len(wave)
+	}
+
+	client, err := server.NewClient()
+	if err != nil {
+		return exitcode.ConnectionError(err)
+	}
+
+	defer func(client *server.GrpcClient) {
+		_ = client.Close()
+	}(client)
+
+	ctx := context.Background()
+
+	if !importNoCreate {
+		if err := createMissingFamilies(ctx, client, families); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Importing %d rows from %d records with %d workers...\n",
+		total, len(records), importConcurrency)
+
+	var (
+		written  atomic.Int64
+		failed   atomic.Int64
+		mu       sync.Mutex
+		failures []importFailure
+	)
+
+	// Report progress on stderr until every wave has finished
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fmt.Fprintf(os.Stderr, "\r%d/%d rows written, %d failed",
+					written.Load(), total, failed.Load())
+			}
+		}
+	}()
+
+	// Waves are written one after another so repeated versions keep their order
+	for _, wave := range waves {
+		var wg sync.WaitGroup
+		jobs := make(chan importBatch)
+		for i := 0; i < importConcurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for b := range jobs {
+					_, err := client.Write(ctx, &server.WriteParams{
+						Key:        b.key,
+						Family:     b.family,
+						Qualifiers: b.qualifiers,
+					})
+					if err != nil {
+						failed.Add(1)
+						mu.Lock()
+						failures = append(failures, importFailure{key: b.key, family: b.family, err: err})
+						mu.Unlock()
+						continue
+					}
+					written.Add(1)
+				}
+			}()
+		}
+
+		for _, b := range wave {
+			jobs <- b
+		}
+		close(jobs)
+		wg.Wait()
+	}
+	close(done)
+
+	elapsed := time.Since(start)
+	fmt.Fprintf(os.Stderr, "\r%d/%d rows written, %d failed\n",
+		written.Load(), total, failed.Load())
+	fmt.Fprintf(os.Stderr, "Import duration: %s (%.1f rows/s)\n",
+		elapsed, float64(written.Load())/elapsed.Seconds())
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].key < failures[j].key
+	})
+	fmt.Fprintln(os.Stderr, "Failed rows:")
+	for i, f := range failures {
+		if i == importMaxErrors {
+			fmt.Fprintf(os.Stderr, "  ... and %d more\n", len(failures)-i)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  %s (%s): %v\n", f.key, f.family, f.err)
+	}
+
+	return exitcode.New(exitcode.Code(failures[0].err),
+		fmt.Errorf("%d of %d rows failed to import", len(failures), total))
+}
+
+// buildImportBatches validates the records and merges those sharing a row key and family
+// into a single write, returning the writes grouped in waves along with the distinct
+// families the records reference. A qualifier that repeats for the same row (such as an
+// export with every version) starts a new wave, and the waves are written in order so
+// later versions land after earlier ones.
+func buildImportBatches(records []litetable.Record) ([][]importBatch, []string, error) {
+	type batchKey struct{ key, family string }
+	type batchRef struct {
+		wave, pos int
+		names     map[string]bool
+	}
+
+	var waves [][]importBatch
+	latest := make(map[batchKey]*batchRef)
+	seenFamilies := make(map[string]bool)
+	var families []string
+
+	for i, rec := range records {
+		if err := rec.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+
+		if !seenFamilies[rec.Family] {
+			seenFamilies[rec.Family] = true
+			families = append(families, rec.Family)
+		}
+
+		// Sort qualifier names so writes are deterministic
+		names := make([]string, 0, len(rec.Qualifiers))
+		for name := range rec.Qualifiers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		k := batchKey{key: rec.RowKey, family: rec.Family}
+		ref, ok := latest[k]
+		if ok {
+			for _, name := range names {
+				if ref.names[name] {
+					ok = false
+					break
+				}
+			}
+		}
+
+		if !ok {
+			wave := 0
+			if ref != nil {
+				wave = ref.wave + 1
+			}
+			if wave == len(waves) {
+				waves = append(waves, nil)
+			}
+			ref = &batchRef{wave: wave, pos: len(waves[wave]), names: make(map[string]bool)}
+			waves[wave] = append(waves[wave], importBatch{key: rec.RowKey, family: rec.Family})
+			latest[k] = ref
+		}
+
+		batch := &waves[ref.wave][ref.pos]
+		for _, name := range names {
+			value, err := litetable.ValueBytes(rec.Qualifiers[name])
+			if err != nil {
+				return nil, nil, fmt.Errorf("record %d: qualifier %q: %w", i+1, name, err)
+			}
+			ref.names[name] = true
+			batch.qualifiers = append(batch.qualifiers, server.Qualifier{
+				Name:  name,
+				Value: value,
+			})
+		}
+	}
+
+	return waves, families, nil
+}
+
+// createMissingFamilies makes sure every family exists on the target server
+func createMissingFamilies(ctx context.Context, client *server.GrpcClient, families []string) error {
+	if len(families) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Creating families: %v\n", families)
+	if err := client.EnsureFamilies(ctx, families); err != nil {
+		return fmt.Errorf("failed to create families: %w", err)
+	}
+
+	return nil
+}
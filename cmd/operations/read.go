@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/output"
 	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
+	"os"
 	"time"
 )
 
@@ -17,6 +20,8 @@ var (
 	readFamily    string
 	readQualifier []string
 	readLatest    int
+	readOutput    string
+	readFormat    output.Format
 
 	ReadCmd = &cobra.Command{
 		Use:   "read",
@@ -38,10 +43,16 @@ var (
 			if selectors != 1 {
 				return fmt.Errorf("exactly one of --key (-k), --keyPrefix (-p), or --regex (-r) must be provided")
 			}
+
+			format, err := output.ParseFormat(readOutput)
+			if err != nil {
+				return err
+			}
+			readFormat = format
 			return nil
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			readData()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return readData()
 		},
 	}
 )
@@ -56,12 +67,14 @@ func init() {
 	ReadCmd.Flags().StringVarP(&readFamily, "family", "f", "", "Column family to read")
 	ReadCmd.Flags().StringArrayVarP(&readQualifier, "qualifier", "q", []string{}, "Qualifiers to read (can be specified multiple times)")
 	ReadCmd.Flags().IntVarP(&readLatest, "latest", "l", 0, "Number of latest versions to return")
+	ReadCmd.Flags().StringVarP(&readOutput, "output", "o", string(output.Text),
+		"Output format: text, json, ndjson, yaml, csv or table")
 
 	// Mark required flags - removing the required mark for key
 	_ = ReadCmd.MarkFlagRequired("family")
 }
 
-func readData() {
+func readData() error {
 	now := time.Now()
 
 	// Build the READ command based on which selector is provided
@@ -87,8 +100,7 @@ func readData() {
 
 	client, err := server.NewClient()
 	if err != nil {
-		fmt.Printf("%v", err)
-		return
+		return exitcode.ConnectionError(err)
 	}
 
 	defer func(client *server.GrpcClient) {
@@ -105,23 +117,16 @@ func readData() {
 	data, err := client.Read(context.Background(), &opts)
 	if err != nil {
 		if errors.Is(err, server.ErrRowNotFound) {
-			fmt.Println("row not found")
-			return
+			return exitcode.NotFoundError(err)
 		}
-		fmt.Printf("failed to read data: %w", err)
-		return
+		return fmt.Errorf("failed to read data: %w", err)
 	}
 
-	first := true
-	// Print the rows
-	for _, row := range data {
-		if !first {
-			fmt.Println("--------------------")
-			fmt.Println()
-		}
-		first = false
-		fmt.Printf("%s\n", row.PrettyPrint())
+	// Results go to stdout; the summary goes to stderr so scripts can consume the output
+	if err = output.Rows(os.Stdout, readFormat, data); err != nil {
+		return fmt.Errorf("failed to render results: %w", err)
 	}
-	fmt.Printf("Row results: %d\n", len(data))
-	fmt.Printf("Query duration: %s\n", time.Since(now))
+	fmt.Fprintf(os.Stderr, "Row results: %d\n", len(data))
+	fmt.Fprintf(os.Stderr, "Query duration: %s\n", time.Since(now))
+	return nil
 }
@@ -2,10 +2,13 @@ package operations
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/output"
 	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
-	"net/url"
+	"os"
 	"time"
 )
 
@@ -14,14 +17,20 @@ var (
 	writeFamily string
 	writeQuals  []string
 	writeValues []string
+	writeFiles  []string
+	writeBase64 bool
 	writeTTL    int64
+	writeOutput string
+	writeFormat output.Format
 
 	WriteCmd = &cobra.Command{
 		Use:   "write",
 		Short: "Write data to the Litetable server",
 		Long:  "Write allows you to send data to the Litetable server",
 		Example: "litetable write --key=rowKey --family=familyName --qualifier=qual1 --value=val1" +
-			" --qualifier=qual2 --value=val2 --ttl=60",
+			" --qualifier=qual2 --value=val2\n\n" +
+			"litetable write -k rowKey -f familyName -q avatar --value-file ./avatar.png\n\n" +
+			"litetable write -k rowKey -f familyName -q blob --value-base64 --value AAECAw==",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			// Validate inputs
 			if writeKey == "" {
@@ -30,20 +39,34 @@ var (
 			if writeFamily == "" {
 				return fmt.Errorf("family is required")
 			}
-			if len(writeQuals) != len(writeValues) {
+			if len(writeValues) > 0 && len(writeFiles) > 0 {
+				return fmt.Errorf("--value and --value-file cannot be combined")
+			}
+			if writeBase64 && len(writeFiles) > 0 {
+				return fmt.Errorf("--value-base64 applies to --value only; files are written as-is")
+			}
+			if values := len(writeValues) + len(writeFiles); len(writeQuals) != values {
 				return fmt.Errorf("number of qualifiers (%d) must match number of values (%d)",
-					len(writeQuals), len(writeValues))
+					len(writeQuals), values)
 			}
 			if len(writeQuals) == 0 {
 				return fmt.Errorf("at least one qualifier/value pair is required")
 			}
-			if writeTTL < 0 {
-				return fmt.Errorf("TTL must be a non-negative value")
+			if writeTTL != 0 {
+				// The server API the CLI is built with cannot send a TTL with a write yet, and
+				// silently dropping it would leave values that never expire
+				return fmt.Errorf("--ttl is not supported yet: the server API does not accept write TTLs")
 			}
+
+			format, err := output.ParseFormat(writeOutput)
+			if err != nil {
+				return err
+			}
+			writeFormat = format
 			return nil
 		},
-		Run: func(cmd *cobra.Command, args []string) {
-			writeData()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeData()
 		},
 	}
 )
@@ -56,34 +79,41 @@ func init() {
 		"Qualifiers to read (can be specified multiple times)")
 	WriteCmd.Flags().StringArrayVarP(&writeValues, "value", "v", []string{},
 		"Values to write (can be specified multiple times, use quotes for values with spaces)")
+	WriteCmd.Flags().StringArrayVar(&writeFiles, "value-file", []string{},
+		"Files whose exact contents are written as values (can be specified multiple times)")
+	WriteCmd.Flags().BoolVar(&writeBase64, "value-base64", false,
+		"Decode every --value as standard base64 before writing")
 	WriteCmd.Flags().Int64VarP(&writeTTL, "ttl", "t", 0,
-		"Time to live in seconds (0 means no expiration)")
+		"Time to live in seconds (not supported by the server API yet)")
+	WriteCmd.Flags().StringVarP(&writeOutput, "output", "o", string(output.Text),
+		"Output format: text, json, ndjson, yaml, csv or table")
 }
 
-func writeData() {
+func writeData() error {
 	start := time.Now()
+	values, err := writeValueBytes()
+	if err != nil {
+		return err
+	}
+
 	var quals []server.Qualifier
 	// Create the WRITE command with all the qualifier/value pairs
 	for i := 0; i < len(writeQuals); i++ {
-		// URL encode the value to properly handle spaces and special characters
-		encodedValue := url.QueryEscape(writeValues[i])
 		quals = append(quals, server.Qualifier{
 			Name:  writeQuals[i],
-			Value: encodedValue,
+			Value: values[i],
 		})
 	}
 
 	client, err := server.NewClient()
 	if err != nil {
-		fmt.Printf("%v", err)
-		return
+		return exitcode.ConnectionError(err)
 	}
 
 	defer func(client *server.GrpcClient) {
 		_ = client.Close()
 	}(client)
 
-	// TODO: fix the ttl stuff after server
 	opts := server.WriteParams{
 		Key:        writeKey,
 		Family:     writeFamily,
@@ -91,21 +121,45 @@ func writeData() {
 	}
 	data, err := client.Write(context.Background(), &opts)
 	if err != nil {
-		fmt.Printf("%v\n", err)
-		return
+		return fmt.Errorf("failed to write data: %w", err)
 	}
 
-	first := true
-	for _, row := range data {
-		if !first {
-			fmt.Println("--------------------")
-			fmt.Println()
-		} else {
-			fmt.Println()
+	// Results go to stdout; the summary goes to stderr so scripts can consume the output
+	if err = output.Rows(os.Stdout, writeFormat, data); err != nil {
+		return fmt.Errorf("failed to render results: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Query duration: %s\n", time.Since(start))
+	return nil
+}
+
+// writeValueBytes returns the exact bytes to write for each qualifier, read from
+// --value-file or taken from --value (base64 decoded with --value-base64).
+func writeValueBytes() ([][]byte, error) {
+	var values [][]byte
+
+	for _, path := range writeFiles {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, exitcode.NotFoundError(fmt.Errorf("value file not found: %s", path))
+			}
+			return nil, fmt.Errorf("failed to read value file: %w", err)
+		}
+		values = append(values, b)
+	}
+
+	for i, v := range writeValues {
+		if !writeBase64 {
+			values = append(values, []byte(v))
+			continue
 		}
-		first = false
-		fmt.Printf("%s\n", row.PrettyPrint())
 
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, exitcode.UsageError(fmt.Errorf("value %d is not valid base64: %w", i+1, err))
+		}
+		values = append(values, b)
 	}
-	fmt.Printf("Query duration: %s\n", time.Since(start))
+
+	return values, nil
 }
@@ -4,19 +4,39 @@ import (
 	"fmt"
 	"github.com/litetable/litetable-cli/cmd/dashboard"
 	"github.com/litetable/litetable-cli/cmd/operations"
+	"github.com/litetable/litetable-cli/cmd/shell"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 var (
+	contextName   string
+	serverAddress string
+
 	rootCmd = &cobra.Command{
 		Use:     "litetable",
 		Example: "litetable --help\n\nlitetable service init",
 		Short:   "A CLI tool for interacting with litetable",
 		Long: "Litetable is a high-performance key-value store designed with local" +
-			" development in mind. Proudly written in pure Go.\n",
+			" development in mind. Proudly written in pure Go.\n\n" +
+			"Exit codes:\n" +
+			"  0  success\n" +
+			"  1  general failure\n" +
+			"  2  usage error (invalid flags, arguments or configuration values)\n" +
+			"  3  connection failure (the server could not be reached)\n" +
+			"  4  not found (row, file or process does not exist)\n" +
+			"  5  server error (the server rejected the request)\n",
 		Run: func(cmd *cobra.Command, args []string) {
 			_ = cmd.Help()
 		},
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			profile.SetOverride(contextName, serverAddress)
+		},
+		// Errors are reported once by Execute, on stderr
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 )
 
@@ -26,19 +46,66 @@ func init() {
 	rootCmd.AddCommand(operations.ReadCmd)
 	rootCmd.AddCommand(operations.WriteCmd)
 	rootCmd.AddCommand(operations.DeleteCmd)
+	rootCmd.AddCommand(operations.ImportCmd)
+	rootCmd.AddCommand(operations.ExportCmd)
 	rootCmd.AddCommand(dashboard.Command)
+	rootCmd.AddCommand(shell.Command)
 
 	rootCmd.AddCommand(serviceCmd)
 	rootCmd.AddCommand(UpdateCmd)
 
 	rootCmd.AddCommand(uninstallCommand)
 	rootCmd.AddCommand(versionCommand)
+	rootCmd.AddCommand(doctorCmd)
 
 	rootCmd.AddCommand(wipeCmd)
+
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "",
+		"Connection context to use for this command (see 'litetable context')")
+	rootCmd.PersistentFlags().StringVar(&serverAddress, "server", "",
+		"Server to connect to as host[:rpc_port], overriding the context")
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return exitcode.UsageError(err)
+	})
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err.Error())
+	markRunErrors(rootCmd)
+
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	// Anything cobra rejects before a command runs (unknown commands, missing required
+	// flags, bad arguments, PreRunE validation) is a usage error.
+	code := exitcode.Usage
+	if exitcode.Classified(err) {
+		code = exitcode.Code(err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if code == exitcode.Usage {
+		fmt.Fprintf(os.Stderr, "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	os.Exit(code)
+}
+
+// markRunErrors wraps every RunE in the command tree so errors returned while a command
+// is running carry an exit code, which separates them from cobra's own usage errors.
+func markRunErrors(cmd *cobra.Command) {
+	if runE := cmd.RunE; runE != nil {
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			err := runE(c, args)
+			if err != nil && !exitcode.Classified(err) {
+				return exitcode.New(exitcode.Code(err), err)
+			}
+			return err
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		markRunErrors(child)
 	}
 }
@@ -19,7 +19,16 @@ func init() {
 	serviceCmd.AddCommand(service.InitCommand)
 	serviceCmd.AddCommand(service.StartCommand)
 	serviceCmd.AddCommand(service.StopCommand)
+	serviceCmd.AddCommand(service.RestartCmd)
+	serviceCmd.AddCommand(service.RunCmd)
 	// serviceCmd.AddCommand(service.CredentialsCmd)
 	serviceCmd.AddCommand(service.UpdateCommand)
+	serviceCmd.AddCommand(service.VersionsCmd)
+	serviceCmd.AddCommand(service.UseCmd)
+	serviceCmd.AddCommand(service.RollbackCmd)
 	serviceCmd.AddCommand(service.HealthCmd)
+	serviceCmd.AddCommand(service.StatusCmd)
+	serviceCmd.AddCommand(service.LogsCmd)
+	serviceCmd.AddCommand(service.WaitCmd)
+	serviceCmd.AddCommand(service.LogWriterCmd)
 }
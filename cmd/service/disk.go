@@ -0,0 +1,12 @@
+package service
+
+import "syscall"
+
+// freeDiskSpace returns the bytes available to unprivileged users on the file system of path
+func freeDiskSpace(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/doctor"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/litetable/litetable-cli/internal/server"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// doctorDialTimeout bounds each port and RPC probe of the doctor
+	doctorDialTimeout = 2 * time.Second
+
+	// Free disk space below which the doctor warns, and below which it fails
+	lowDiskSpace      = 1 << 30
+	criticalDiskSpace = 100 << 20
+)
+
+// DoctorChecks returns the checks of the server installation run by 'litetable doctor'
+func DoctorChecks() []doctor.Check {
+	return []doctor.Check{
+		checkTools,
+		checkLayout,
+		checkProcess,
+		checkReachability,
+		checkDiskSpace,
+	}
+}
+
+// checkTools looks for git and go, which are needed to build the server from source
+func checkTools() []doctor.Result {
+	method, _ := config.Get(config.InstallMethod)
+	needed := method == originGit || method == originCheckout
+
+	tools := []struct{ name, versionArg, url string }{
+		{"git", "--version", "https://git-scm.com/downloads"},
+		{"go", "version", "https://go.dev/doc/install"},
+	}
+
+	var results []doctor.Result
+	for _, t := range tools {
+		out, err := exec.Command(t.name, t.versionArg).Output()
+		if err == nil {
+			results = append(results, doctor.OK(t.name, "%s", strings.TrimSpace(string(out))))
+			continue
+		}
+
+		fix := fmt.Sprintf("install %s from %s", t.name, t.url)
+		if needed {
+			results = append(results, doctor.Fail(t.name,
+				fmt.Sprintf("not found, but the server is built from source (install_method = %s)", method), fix))
+		} else {
+			results = append(results, doctor.Warn(t.name,
+				"not found; only needed to build the server from source", fix))
+		}
+	}
+	return results
+}
+
+// checkLayout checks ~/.litetable and the installed server binary
+func checkLayout() []doctor.Result {
+	name := "installation"
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return []doctor.Result{doctor.Fail(name, err.Error(), "make sure $HOME is set")}
+	}
+
+	info, err := os.Stat(liteTableDir)
+	if err != nil || !info.IsDir() {
+		return []doctor.Result{doctor.Fail(name, fmt.Sprintf("%s does not exist", liteTableDir),
+			"run 'litetable service init' to install the server")}
+	}
+
+	var results []doctor.Result
+	if f, err := os.CreateTemp(liteTableDir, ".doctor-*"); err != nil {
+		results = append(results, doctor.Fail(name, fmt.Sprintf("%s is not writable: %v", liteTableDir, err),
+			fmt.Sprintf("make %s writable by your user, e.g. 'chown -R $USER %s'", liteTableDir, liteTableDir)))
+	} else {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	binPath, err := configuredBinary(liteTableDir, stableBinaryPath(liteTableDir))
+	if err != nil {
+		return append(results, doctor.Fail(name, err.Error(), "run 'litetable service init'"))
+	}
+	info, err = os.Stat(binPath)
+	switch {
+	case err != nil:
+		results = append(results, doctor.Fail(name, fmt.Sprintf("server binary %s not found", binPath),
+			"run 'litetable service init --force', or 'litetable service use <version>' to pick an installed version"))
+	case runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0:
+		results = append(results, doctor.Fail(name, fmt.Sprintf("server binary %s is not executable", binPath),
+			fmt.Sprintf("run 'chmod +x %s'", binPath)))
+	default:
+		results = append(results, doctor.OK(name, "server binary %s", binPath))
+	}
+
+	// A dangling bin/litetable-server breaks autostart units, which run that path
+	stable := stableBinaryPath(liteTableDir)
+	if _, err := os.Lstat(stable); err == nil {
+		if _, err := os.Stat(stable); err != nil {
+			results = append(results, doctor.Warn(name, fmt.Sprintf("%s points to a missing version", stable),
+				"run 'litetable service use <version>' with one of 'litetable service versions'"))
+		}
+	}
+	return results
+}
+
+// checkProcess compares the PID file with the process table and the configured ports
+func checkProcess() []doctor.Result {
+	name := "process"
+	pidFile, _ := pidFilePath()
+
+	running, pid, err := checkProcessRunning()
+	if err != nil {
+		return []doctor.Result{doctor.Fail(name, err.Error(),
+			fmt.Sprintf("stop the server if it runs and delete %s", pidFile))}
+	}
+
+	var results []doctor.Result
+	switch {
+	case running:
+		results = append(results, doctor.OK(name, "server is running with PID %d", pid))
+	case pid != 0:
+		results = append(results, doctor.Warn(name, fmt.Sprintf("PID file names PID %d, which is not a "+
+			"running LiteTable server", pid),
+			fmt.Sprintf("run 'litetable service start', which cleans it up, or delete %s", pidFile)))
+	default:
+		results = append(results, doctor.OK(name, "server is not running"))
+	}
+
+	target, err := profile.LocalTarget()
+	if err != nil {
+		// The configuration check reports missing ports
+		return results
+	}
+
+	var owned map[int]bool
+	if running && runtime.GOOS == "linux" {
+		owned, _ = listeningPorts(pid)
+	}
+
+	for _, p := range []struct{ key, value string }{
+		{config.ServerPort, target.HTTPPort},
+		{config.ServerRPCPort, target.RPCPort},
+	} {
+		if p.value == "" {
+			continue
+		}
+		address := net.JoinHostPort(target.Address, p.value)
+		inUse := portInUse(address)
+		port, _ := strconv.Atoi(p.value)
+
+		switch {
+		case running && !inUse:
+			results = append(results, doctor.Fail(name,
+				fmt.Sprintf("the server runs, but nothing listens on %s %s", p.key, address),
+				"check 'litetable service logs' and run 'litetable service restart'"))
+		case running && owned != nil && !owned[port]:
+			results = append(results, doctor.Warn(name,
+				fmt.Sprintf("%s %s is served by another process than PID %d", p.key, address, pid),
+				fmt.Sprintf("stop the other process, or change %s with 'litetable config update'", p.key)))
+		case !running && inUse && autostartInstalled():
+			results = append(results, doctor.Warn(name,
+				fmt.Sprintf("%s %s is in use, probably by the server started by autostart", p.key, address),
+				"manage the autostarted server with your service manager, or run 'litetable service stop' first"))
+		case !running && inUse:
+			results = append(results, doctor.Fail(name,
+				fmt.Sprintf("%s %s is in use by another process", p.key, address),
+				fmt.Sprintf("stop that process, or change %s with 'litetable config update'", p.key)))
+		case running:
+			results = append(results, doctor.OK(name, "%s %s is listening", p.key, address))
+		}
+	}
+	return results
+}
+
+// checkReachability probes the HTTP and RPC endpoints of the server of the active context
+func checkReachability() []doctor.Result {
+	name := "connectivity"
+	target, err := profile.Resolve()
+	if err != nil {
+		return []doctor.Result{doctor.Fail(name, err.Error(),
+			"run 'litetable service init', or select a context with 'litetable context use'")}
+	}
+
+	// Probing a stopped local server only repeats that it is not running
+	if target.Name == profile.Local && !isServerRunning() && !portInUse(target.RPCAddress()) {
+		return []doctor.Result{doctor.Warn(name, "the local server is not running, endpoints not checked",
+			"run 'litetable service start'")}
+	}
+
+	fix := "check server_address and the ports in litetable.conf, and 'litetable service logs'"
+	if target.Name != profile.Local {
+		fix = fmt.Sprintf("check the address of context %s with 'litetable context list'", target.Name)
+	}
+
+	var results []doctor.Result
+	if target.HTTPPort != "" {
+		code, _, err := fetchHealth(target)
+		switch {
+		case err != nil:
+			results = append(results, doctor.Fail(name,
+				fmt.Sprintf("HTTP %s is not reachable: %v", target.HTTPURL("/health"), err), fix))
+		case code != http.StatusOK:
+			results = append(results, doctor.Fail(name,
+				fmt.Sprintf("HTTP %s returned %d", target.HTTPURL("/health"), code),
+				"check 'litetable service logs' for errors"))
+		default:
+			results = append(results, doctor.OK(name, "HTTP %s is OK", target.HTTPURL("/health")))
+		}
+	}
+
+	// Compatibility has a check of its own, so the probe does not warn about it
+	probe := *target
+	probe.ServerVersion = ""
+	client, err := server.NewClientForTarget(&probe)
+	if err != nil {
+		return append(results, doctor.Fail(name, fmt.Sprintf("RPC %s: %v", target.RPCAddress(), err), fix))
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorDialTimeout)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		return append(results, doctor.Fail(name,
+			fmt.Sprintf("RPC %s is not reachable: %v", target.RPCAddress(), err), fix))
+	}
+	return append(results, doctor.OK(name, "RPC %s answers", target.RPCAddress()))
+}
+
+// checkDiskSpace checks the free space where the server keeps its data
+func checkDiskSpace() []doctor.Result {
+	name := "disk space"
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return nil
+	}
+
+	// Before init the directory does not exist yet; it is created in the home directory
+	path := liteTableDir
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Dir(path)
+	}
+
+	free, err := freeDiskSpace(path)
+	if err != nil {
+		return []doctor.Result{doctor.Warn(name, fmt.Sprintf("could not determine free space: %v", err), "")}
+	}
+
+	detail := fmt.Sprintf("%s free on %s", formatBytes(int64(free)), path)
+	fix := "free up space; 'litetable service status' shows what the data directories use"
+	switch {
+	case free < criticalDiskSpace:
+		return []doctor.Result{doctor.Fail(name, detail, fix)}
+	case free < lowDiskSpace:
+		return []doctor.Result{doctor.Warn(name, detail, fix)}
+	}
+	return []doctor.Result{doctor.OK(name, "%s", detail)}
+}
+
+// portInUse reports whether something accepts connections on address
+func portInUse(address string) bool {
+	conn, err := net.DialTimeout("tcp", address, doctorDialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
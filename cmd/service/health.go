@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"fmt"
-	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/litetable/litetable-cli/internal/server"
 	"github.com/spf13/cobra"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -15,58 +18,75 @@ var HealthCmd = &cobra.Command{
 	Use:   "health",
 	Short: "Check if the LiteTable server is running",
 	Long:  `Sends a PING request to the LiteTable server to verify it's operational.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		checkServerHealth()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return checkServerHealth()
 	},
 }
 
-func checkServerHealth() {
+func checkServerHealth() error {
 	fmt.Println("🔍 Checking LiteTable server health...")
+
+	target, err := profile.Resolve()
+	if err != nil {
+		return exitcode.ConnectionError(err)
+	}
+	if target.HTTPPort == "" {
+		return exitcode.UsageError(fmt.Errorf("no HTTP port configured for context %q", target.Name))
+	}
+
+	statusCode, body, err := fetchHealth(target)
+	if err != nil {
+		return exitcode.ConnectionError(fmt.Errorf("server health check failed: %w", err))
+	}
+
+	// Check status code
+	if statusCode != http.StatusOK {
+		return exitcode.New(exitcode.Server, fmt.Errorf(
+			"server health check returned non-OK status: %d - %s", statusCode, body))
+	}
+
+	fmt.Println("✅  LiteTable server is healthy!")
+	fmt.Printf("%s\n", body)
+	return nil
+}
+
+// fetchHealth calls the /health endpoint of the target, over https when it uses TLS, and
+// returns the status code and body of the response.
+func fetchHealth(target *profile.Target) (int, string, error) {
 	// Create HTTP client with timeout
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 	}
-
-	serverAddress, _ := litetable.GetFromConfig(litetable.ServerAddress)
-	port, _ := litetable.GetFromConfig(litetable.ServerPort)
-
-	// Build the URL
-	url := fmt.Sprintf("http://%s:%s/health", serverAddress, port)
+	if target.TLS.Enabled {
+		tlsCfg, err := server.TLSConfig(target.TLS)
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to configure TLS: %w", err)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: tlsCfg}
+	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.HTTPURL("/health"), nil)
 	if err != nil {
-		fmt.Printf("failed to create request: %v", err)
-		return
+		return 0, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Send request
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("server health check failed: %v", err)
-		return
+		return 0, "", err
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("failed to read response body: %v`", err)
-		return
+		return 0, "", fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("server health check returned non-OK status: %d - %s",
-			resp.StatusCode, string(body))
-		return
-	}
-
-	fmt.Println("✅  LiteTable server is healthy!")
-	fmt.Printf("%s\n", string(body))
-
+	return resp.StatusCode, strings.TrimSpace(string(body)), nil
 }
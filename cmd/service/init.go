@@ -2,7 +2,9 @@ package service
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
 	"github.com/litetable/litetable-cli/internal/dir"
 	"github.com/litetable/litetable-cli/internal/litetable"
 	"github.com/spf13/cobra"
@@ -21,12 +23,12 @@ var (
 	InitCommand = &cobra.Command{
 		Use:   "init",
 		Short: "Initialize LiteTable database",
-		Long:  "Pull, build, and configure the latest version of LiteTable database server",
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := initLiteTable(); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				return
-			}
+		Long: "Install and configure the latest version of LiteTable database server. A prebuilt " +
+			"release archive for this platform is downloaded and verified against the release " +
+			"checksums (and signature, with --public-key); the server is only built from source, " +
+			"which needs git and Go, when no archive exists or with --from-source.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return initLiteTable()
 		},
 	}
 )
@@ -36,6 +38,17 @@ func init() {
 		"Force reinstallation if already installed")
 	InitCommand.Flags().BoolVarP(&autostart, "autostart", "a", false,
 		"Configure server to start automatically")
+	addInstallFlags(InitCommand)
+	InitCommand.Flags().StringVar(&installVersion, "version", "",
+		"Install a specific version (tag or branch) instead of the latest")
+	InitCommand.Flags().StringVar(&installCheckout, "source", "",
+		"Build the server from a local litetable-db checkout, without network access")
+	InitCommand.Flags().StringVar(&serverRepo, "repo", litetable.DatabaseURL,
+		"Server repository to install releases or sources from")
+	InitCommand.MarkFlagsMutuallyExclusive("source", "archive")
+	InitCommand.MarkFlagsMutuallyExclusive("source", "version")
+	InitCommand.MarkFlagsMutuallyExclusive("source", "repo")
+	InitCommand.MarkFlagsMutuallyExclusive("source", "from-source")
 }
 
 func initLiteTable() error {
@@ -48,12 +61,12 @@ func initLiteTable() error {
 	}
 
 	// Check if LiteTable is already installed
-	binPath := filepath.Join(liteTableDir, "bin", serverBin)
-	if runtime.GOOS == "windows" {
-		binPath += ".exe"
+	versions, err := installedVersions(liteTableDir)
+	if err != nil {
+		return err
 	}
 
-	if _, err := os.Stat(binPath); err == nil {
+	if _, err := os.Lstat(stableBinaryPath(liteTableDir)); err == nil || len(versions) > 0 {
 		if !forceInit {
 			fmt.Println("\n⚠️ LiteTable server appears to be already installed.")
 			fmt.Print("Would you like to reinstall? (y/n): ")
@@ -70,90 +83,68 @@ func initLiteTable() error {
 		}
 	}
 
-	// Create the necessary directories
-	binDir := filepath.Join(liteTableDir, "bin")
-	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return fmt.Errorf("failed to create bin directory: %w", err)
-	}
-
-	// Check prerequisites
-	if !checkGitInstalled() {
-		return fmt.Errorf("git is not installed. Please install Git (https://git-scm.com/downloads) and try again")
-	}
-	fmt.Println("\n✅  Git installation detected\n\n📋 Checking prerequisites...")
-
-	if !checkGoInstalled() {
-		return fmt.Errorf("\ngo is not installed. Please install Go (https://go." +
-			"dev/doc/install) and try again")
+	// Keep a server installed before versioned installs, so it can be rolled back to
+	previousVersion, _ := config.Get(config.ServerVersion)
+	if err := adoptLegacyBinary(liteTableDir, previousVersion); err != nil {
+		return err
 	}
 
-	fmt.Println("\n✅  Go installation detected")
-
-	// Get latest version tag
-	fmt.Println("\n🔍 Determining latest version...")
-	latestVersion, err := litetable.GetLatestVersion(litetable.DatabaseURL)
+	// Determine the version to install
+	fmt.Println("\n🔍 Determining version to install...")
+	latestVersion, err := serverVersionToInstall()
 	if err != nil {
-		return fmt.Errorf("failed to determine latest version: %w", err)
+		return fmt.Errorf("failed to determine version: %w", err)
 	}
-	fmt.Printf("\n✅  Latest version: %s\n", latestVersion)
+	fmt.Printf("\n✅  Version to install: %s\n", latestVersion)
 
-	// Clone/update repo and build
-	fmt.Println("\n📥  Downloading latest LiteTable server...")
-	tempDir, err := os.MkdirTemp("", "litetable-build")
+	// Create the necessary directories
+	binPath, err := versionBinaryPath(liteTableDir, latestVersion)
 	if err != nil {
-		return fmt.Errorf("failed to create temporary directory: %w", err)
+		return err
 	}
-	defer func(path string) {
-		err := os.RemoveAll(path)
-		if err != nil {
-		}
-	}(tempDir)
-
-	// Clone the repository
-	fmt.Printf("\n📥  Cloning LiteTable server repository (version %s)...\n", latestVersion)
-	gitCloneCmd := exec.Command("git", "-c", "advice.detachedHead=false", "clone", "--depth", "1", "--branch", latestVersion, serverRepo, tempDir)
-
-	gitCloneCmd.Stdout = os.Stdout
-	gitCloneCmd.Stderr = os.Stderr
-	if err := gitCloneCmd.Run(); err != nil {
-		return fmt.Errorf("failed to clone server repository: %w", err)
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
-	// Build the server
-	fmt.Printf("\n🎯 Building for %s/%s\n", runtime.GOOS, runtime.GOARCH)
-
-	buildCmd := exec.Command("go", "build", "-o", binPath)
-	// Set build environment variables to ensure correct OS/architecture targeting
-	buildCmd.Env = append(os.Environ(),
-		fmt.Sprintf("GOOS=%s", runtime.GOOS),
-		fmt.Sprintf("GOARCH=%s", runtime.GOARCH))
-	buildCmd.Dir = tempDir // Run the build command in the cloned repository directory
-	buildCmd.Stdout = os.Stdout
-	buildCmd.Stderr = os.Stderr
-	if err := buildCmd.Run(); err != nil {
-		return fmt.Errorf("failed to build server: %w", err)
+	origin, err := installServer(latestVersion, binPath)
+	if err != nil {
+		return err
 	}
-
-	// Make server executable (especially important for Unix systems)
-	if runtime.GOOS != "windows" {
-		if err := os.Chmod(binPath, 0755); err != nil {
-			return fmt.Errorf("failed to make server executable: %w", err)
-		}
+	if err := linkStableBinary(liteTableDir, binPath); err != nil {
+		return err
 	}
 
-	// Setup autostart if requested
+	// Setup autostart if requested. Units run the stable path, so they follow 'service use'.
 	if autostart {
 		fmt.Println("\n⚙️  Setting up autostart...")
-		if err := setupAutostart(binPath); err != nil {
+		autostartPath := stableBinaryPath(liteTableDir)
+		if runtime.GOOS == "windows" {
+			autostartPath = binPath
+		}
+		if err := setupAutostart(autostartPath); err != nil {
 			return fmt.Errorf("failed to configure autostart: %w", err)
 		}
 	}
 
-	// Write a configuration file
-	configFile := filepath.Join(liteTableDir, "litetable.conf")
-	if err := writeConfigFile(configFile, latestVersion); err != nil {
+	// Reinstalling the active version keeps its rollback target
+	if previousVersion == latestVersion {
+		previousVersion, _ = config.Get(config.PreviousServerVersion)
+	}
+
+	configFile := filepath.Join(liteTableDir, config.FileName)
+	if err := writeInstallConfig(configFile, map[string]string{
+		config.ServerBinary:  binPath,
+		config.ServerVersion: latestVersion,
+		config.InstallMethod: origin.method,
+		config.InstallSource: origin.source,
+	}); err != nil {
 		return fmt.Errorf("failed to write configuration: %w", err)
 	}
+	if previousVersion != "" && previousVersion != latestVersion {
+		if err := config.Set(config.PreviousServerVersion, previousVersion); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+	}
 
 	// Success message
 	fmt.Println("\n✅  LiteTable setup complete!")
@@ -164,6 +155,27 @@ func initLiteTable() error {
 	return nil
 }
 
+// writeInstallConfig records the installed server in the configuration file. A fresh
+// install gets a file with the default settings; a reinstall only updates the install keys
+// and keeps everything else the user configured.
+func writeInstallConfig(path string, values map[string]string) error {
+	f, err := config.LoadFile(path)
+	if errors.Is(err, config.ErrNotInstalled) {
+		return config.WriteFile(path, values)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, key := range []string{config.ServerBinary, config.ServerVersion, config.InstallMethod,
+		config.InstallSource} {
+		if err := f.Set(key, values[key]); err != nil {
+			return err
+		}
+	}
+	return f.Save()
+}
+
 func checkGitInstalled() bool {
 	cmd := exec.Command("git", "--version")
 	return cmd.Run() == nil
@@ -174,44 +186,6 @@ func checkGoInstalled() bool {
 	return cmd.Run() == nil
 }
 
-func writeConfigFile(path string, version string) error {
-	// Get the full path to the binary
-	liteTableDir, err := dir.GetLitetableDir()
-	if err != nil {
-		return err
-	}
-
-	binPath := filepath.Join(liteTableDir, "bin", serverBin)
-	if runtime.GOOS == "windows" {
-		binPath += ".exe"
-	}
-
-	content := fmt.Sprintf(`# DO NOT CHANGE THIS FILE MANUALLY
-server_binary = %s
-%s = %s
-
-########################################
-#### LiteTable Server Configuration ####
-########################################
-
-server_port = 9443
-server_rpc_port = 49786
-server_address = 127.0.0.1
-debug = true
-garbage_collection_timer = 60
-backup_timer = 80
-snapshot_timer = 20
-max_snapshot_limit = 5
-
-## MCP Server settings
-mcp_server_enabled = false
-mcp_server_address = 127.0.0.1
-mcp_server_port = 49787
-`, binPath, litetable.ServerVersionKey, version)
-
-	return os.WriteFile(path, []byte(content), 0644)
-}
-
 func setupAutostart(serverPath string) error {
 	switch runtime.GOOS {
 	case "darwin":
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/release"
+	"github.com/spf13/cobra"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Install methods recorded as install_method in litetable.conf
+const (
+	// originRelease is a prebuilt archive downloaded from the repository's releases
+	originRelease = "release"
+	// originArchive is a local release archive passed with --archive
+	originArchive = "archive"
+	// originGit is a tag or branch cloned from the repository and built
+	originGit = "git"
+	// originCheckout is a local source checkout passed with --source, built in place
+	originCheckout = "checkout"
+)
+
+var (
+	installArchive    string
+	installChecksums  string
+	installPublicKey  string
+	installFromSource bool
+	installVersion    string
+	installCheckout   string
+	installChannel    string
+)
+
+// installOrigin describes where an installed server came from
+type installOrigin struct {
+	method string
+	// source is the repository URL, archive path or checkout path
+	source string
+}
+
+// addInstallFlags registers the flags that choose how the server binary is obtained
+func addInstallFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&installArchive, "archive", "",
+		"Install from a local release archive instead of downloading one")
+	cmd.Flags().StringVar(&installChecksums, "checksums", "",
+		"Checksums file for --archive (default: looked up next to the archive)")
+	cmd.Flags().StringVar(&installPublicKey, "public-key", "",
+		"ed25519 public key (file, PEM or base64) the release checksums must be signed with")
+	cmd.Flags().BoolVar(&installFromSource, "from-source", false,
+		"Build the server from source instead of installing a prebuilt release")
+}
+
+// serverVersionToInstall returns --version, the version of --source or --archive, or the
+// latest release
+func serverVersionToInstall() (string, error) {
+	if installVersion != "" {
+		return installVersion, nil
+	}
+
+	if installCheckout != "" {
+		return checkoutVersion(installCheckout), nil
+	}
+
+	if installArchive != "" {
+		version, ok := release.VersionFromArchive(installArchive)
+		if !ok {
+			return "", fmt.Errorf("cannot determine the version of %s: expected a name like %s, "+
+				"or pass --version", installArchive, release.AssetName("v1.2.3", runtime.GOOS, runtime.GOARCH))
+		}
+		return version, nil
+	}
+
+	configured, _ := config.Get(config.UpdateChannel)
+	channel, err := litetable.ResolveChannel(installChannel, configured)
+	if err != nil {
+		return "", exitcode.UsageError(err)
+	}
+
+	// The latest release never is a pre-release, so other channels look at the tags
+	if !installFromSource && channel == litetable.ChannelStable {
+		if version, err := release.LatestVersion(serverRepo); err == nil {
+			return version, nil
+		}
+	}
+	// Without a published release, the newest tag is what a source build would use
+	return litetable.GetLatestVersionInChannel(serverRepo, channel)
+}
+
+// installServer puts the server binary of version at binPath: built from --source, from
+// --archive, from a downloaded release archive or, when no archive exists for this
+// platform (e.g. for a branch), by building the tag or branch from serverRepo.
+func installServer(version, binPath string) (*installOrigin, error) {
+	if installCheckout != "" {
+		return buildFromCheckout(installCheckout, binPath)
+	}
+
+	// Releases can only be downloaded from a hosted repository, not e.g. a local mirror
+	if installFromSource || !strings.HasPrefix(serverRepo, "https://") {
+		if err := buildFromSource(version, binPath); err != nil {
+			return nil, err
+		}
+		return &installOrigin{method: originGit, source: serverRepo}, nil
+	}
+
+	publicKey := installPublicKey
+	if publicKey == "" {
+		publicKey, _ = config.Get(config.ReleasePublicKey)
+	}
+
+	opts := release.Options{
+		Version:   version,
+		BaseURL:   strings.TrimSuffix(strings.TrimSuffix(serverRepo, "/"), ".git") + "/releases/download",
+		Archive:   installArchive,
+		Checksums: installChecksums,
+		PublicKey: publicKey,
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+
+	origin := &installOrigin{method: originRelease, source: serverRepo}
+	if installArchive != "" {
+		fmt.Printf("\n📦 Installing from %s\n", installArchive)
+		archive, err := filepath.Abs(installArchive)
+		if err != nil {
+			return nil, err
+		}
+		origin = &installOrigin{method: originArchive, source: archive}
+	} else {
+		fmt.Printf("\n📥  Downloading %s...\n", release.AssetName(version, runtime.GOOS, runtime.GOARCH))
+	}
+
+	result, err := release.Install(opts, binPath)
+	if err != nil {
+		if errors.Is(err, release.ErrNoArtifact) && installArchive == "" {
+			fmt.Printf("\n⚠️  No prebuilt release %s for %s/%s, building from source instead\n",
+				version, runtime.GOOS, runtime.GOARCH)
+			if err := buildFromSource(version, binPath); err != nil {
+				return nil, err
+			}
+			return &installOrigin{method: originGit, source: serverRepo}, nil
+		}
+		return nil, fmt.Errorf("failed to install release: %w", err)
+	}
+
+	fmt.Printf("\n✅  Verified %s (sha256 %s)\n", result.Asset, result.SHA256)
+	if result.Signed {
+		fmt.Println("✅  Checksums signature is valid")
+	}
+	return origin, nil
+}
+
+// recordOrigin stores where the installed server came from in litetable.conf
+func recordOrigin(origin *installOrigin) error {
+	f, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := f.Set(config.InstallMethod, origin.method); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := f.Set(config.InstallSource, origin.source); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	if err := f.Save(); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	return nil
+}
+
+// checkoutVersion describes the commit of a local checkout, e.g. v1.4.0-3-gabc1234-dirty.
+// Without git the version is reported as "dev".
+func checkoutVersion(path string) string {
+	out, err := exec.Command("git", "-C", path, "describe", "--tags", "--always", "--dirty").Output()
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return "dev"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// buildFromCheckout builds the server from a local source checkout, without network access
+func buildFromCheckout(path, binPath string) (*installOrigin, error) {
+	checkout, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(checkout, "go.mod")); err != nil {
+		return nil, fmt.Errorf("%s is not a Go module (no go.mod found)", checkout)
+	}
+
+	if !checkGoInstalled() {
+		return nil, fmt.Errorf("\ngo is not installed. Please install Go (https://go." +
+			"dev/doc/install) and try again")
+	}
+
+	fmt.Printf("\n🎯 Building %s for %s/%s\n", checkout, runtime.GOOS, runtime.GOARCH)
+	if err := goBuild(checkout, binPath); err != nil {
+		return nil, err
+	}
+	return &installOrigin{method: originCheckout, source: checkout}, nil
+}
+
+// buildFromSource clones version of the server repository and builds it to binPath
+func buildFromSource(version, binPath string) error {
+	// Check prerequisites
+	fmt.Println("\n📋 Checking prerequisites...")
+	if !checkGitInstalled() {
+		return fmt.Errorf("git is not installed. Please install Git (https://git-scm.com/downloads) and try again")
+	}
+	fmt.Println("\n✅  Git installation detected")
+
+	if !checkGoInstalled() {
+		return fmt.Errorf("\ngo is not installed. Please install Go (https://go." +
+			"dev/doc/install) and try again")
+	}
+	fmt.Println("\n✅  Go installation detected")
+
+	tempDir, err := os.MkdirTemp("", "litetable-build")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Clone the repository
+	fmt.Printf("\n📥  Cloning LiteTable server repository (version %s)...\n", version)
+	gitCloneCmd := exec.Command("git", "-c", "advice.detachedHead=false", "clone", "--depth", "1",
+		"--branch", version, serverRepo, tempDir)
+	gitCloneCmd.Stdout = os.Stdout
+	gitCloneCmd.Stderr = os.Stderr
+	if err := gitCloneCmd.Run(); err != nil {
+		return fmt.Errorf("failed to clone server repository: %w", err)
+	}
+
+	// Build the server
+	fmt.Printf("\n🎯 Building for %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	return goBuild(tempDir, binPath)
+}
+
+// goBuild builds the server module in dir to binPath
+func goBuild(dir, binPath string) error {
+	buildCmd := exec.Command("go", "build", "-o", binPath)
+	// Set build environment variables to ensure correct OS/architecture targeting
+	buildCmd.Env = append(os.Environ(),
+		fmt.Sprintf("GOOS=%s", runtime.GOOS),
+		fmt.Sprintf("GOARCH=%s", runtime.GOARCH))
+	buildCmd.Dir = dir // Run the build command in the repository directory
+	buildCmd.Stdout = os.Stdout
+	buildCmd.Stderr = os.Stderr
+	if err := buildCmd.Run(); err != nil {
+		return fmt.Errorf("failed to build server: %w", err)
+	}
+
+	// Make server executable (especially important for Unix systems)
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(binPath, 0755); err != nil {
+			return fmt.Errorf("failed to make server executable: %w", err)
+		}
+	}
+	return nil
+}
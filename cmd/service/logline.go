@@ -0,0 +1,154 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// logLevel orders the severities found in server logs
+type logLevel int
+
+const (
+	levelUnknown logLevel = iota
+	levelDebug
+	levelInfo
+	levelWarn
+	levelError
+	levelFatal
+)
+
+// levelNames maps the spellings used by common Go loggers to a level
+var levelNames = map[string]logLevel{
+	"trace": levelDebug, "debug": levelDebug, "dbg": levelDebug,
+	"info": levelInfo, "inf": levelInfo,
+	"warn": levelWarn, "warning": levelWarn, "wrn": levelWarn,
+	"error": levelError, "err": levelError, "erro": levelError,
+	"fatal": levelFatal, "ftl": levelFatal, "panic": levelFatal, "pnc": levelFatal,
+}
+
+// parseLevel converts a --level value
+func parseLevel(name string) (logLevel, error) {
+	level, ok := levelNames[strings.ToLower(name)]
+	if !ok {
+		return levelUnknown, fmt.Errorf("unknown log level %q (use debug, info, warn, error or fatal)", name)
+	}
+	return level, nil
+}
+
+var (
+	// levelPattern finds a level written as level=INFO, [WARN], or as a standalone word
+	levelPattern = regexp.MustCompile(
+		`(?i)(?:level=|\[|\b)(trace|debug|dbg|info|inf|warn|warning|wrn|error|err|erro|fatal|ftl|panic|pnc)(?:\]|\b)`)
+
+	// timePatterns find a timestamp at the start of a line (optionally after "time=")
+	timePatterns = []struct {
+		re     *regexp.Regexp
+		layout string
+	}{
+		{regexp.MustCompile(`^(?:time=)?"?(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2}))`),
+			time.RFC3339Nano},
+		{regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?)`), "2006/01/02 15:04:05"},
+		{regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?)`), "2006-01-02 15:04:05"},
+	}
+)
+
+// logLine is a line of a server log with the time and level it was logged at, when known
+type logLine struct {
+	text  string
+	time  time.Time
+	level logLevel
+}
+
+// parseLogLine extracts the timestamp and level of a line. JSON lines are read by their
+// time/level fields; other lines are matched against common text formats. Lines without
+// a timestamp or level, such as stack traces, inherit them from the previous line.
+func parseLogLine(text string, prev *logLine) logLine {
+	line := logLine{text: text}
+
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		var fields map[string]any
+		if json.Unmarshal([]byte(text), &fields) == nil {
+			for _, key := range []string{"time", "ts", "timestamp"} {
+				if s, ok := fields[key].(string); ok {
+					line.time, _ = time.Parse(time.RFC3339Nano, s)
+					break
+				}
+			}
+			for _, key := range []string{"level", "lvl", "severity"} {
+				if s, ok := fields[key].(string); ok {
+					line.level = levelNames[strings.ToLower(s)]
+					break
+				}
+			}
+		}
+	}
+
+	if line.time.IsZero() {
+		for _, p := range timePatterns {
+			if m := p.re.FindStringSubmatch(text); m != nil {
+				// Text formats without a zone are written in local time
+				line.time, _ = time.ParseInLocation(p.layout, m[1], time.Local)
+				break
+			}
+		}
+	}
+
+	if line.level == levelUnknown {
+		if m := levelPattern.FindStringSubmatch(text); m != nil {
+			line.level = levelNames[strings.ToLower(m[1])]
+		}
+	}
+
+	if prev != nil {
+		if line.time.IsZero() {
+			line.time = prev.time
+		}
+		if line.level == levelUnknown {
+			line.level = prev.level
+		}
+	}
+
+	return line
+}
+
+// logFilter selects the lines shown by service logs
+type logFilter struct {
+	since time.Time
+	level logLevel
+	grep  *regexp.Regexp
+}
+
+func (f *logFilter) match(line *logLine) bool {
+	if !f.since.IsZero() && (line.time.IsZero() || line.time.Before(f.since)) {
+		return false
+	}
+	// Lines without a recognisable level are kept so nothing is silently hidden
+	if f.level != levelUnknown && line.level != levelUnknown && line.level < f.level {
+		return false
+	}
+	if f.grep != nil && !f.grep.MatchString(line.text) {
+		return false
+	}
+	return true
+}
+
+// parseSince accepts a duration such as 15m (relative to now) or an RFC 3339 timestamp
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("--since must not be negative")
+		}
+		return now.Add(-d), nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration like 15m or a time like 2006-01-02T15:04:05Z", value)
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+const (
+	// startLogFile receives stdout and stderr of a server launched by 'service start'
+	startLogFile = "litetable.log"
+	// autostartLogFile and autostartErrFile are written by the autostart units
+	autostartLogFile = "server.log"
+	autostartErrFile = "server.err"
+
+	followInterval = 250 * time.Millisecond
+)
+
+var (
+	logsFollow bool
+	logsLines  int
+	logsSince  string
+	logsLevel  string
+	logsGrep   string
+	logsErr    bool
+	logsFile   string
+
+	// LogsCmd represents the logs command
+	LogsCmd = &cobra.Command{
+		Use:   "logs",
+		Short: "Show the LiteTable server logs",
+		Long: "Prints the end of the server log. The log is chosen by how the server runs: " +
+			"litetable.log for a server started with 'service start', or server.log (server.err " +
+			"with --err) for a server launched by autostart. Lines can be filtered by time, " +
+			"minimum level and a regular expression.",
+		Example: "litetable service logs -n 200\n\n" +
+			"litetable service logs -f --level warn\n\n" +
+			"litetable service logs --since 15m --grep 'family|snapshot'",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if logsLines < 0 {
+				return fmt.Errorf("-n must not be negative")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return showLogs()
+		},
+	}
+)
+
+func init() {
+	LogsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new lines as they are written")
+	LogsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "Number of lines to show (0 shows all)")
+	LogsCmd.Flags().StringVar(&logsSince, "since", "",
+		"Only show lines logged after a duration ago (15m) or a time (2006-01-02T15:04:05Z)")
+	LogsCmd.Flags().StringVarP(&logsLevel, "level", "l", "",
+		"Only show lines at or above a level: debug, info, warn, error or fatal")
+	LogsCmd.Flags().StringVarP(&logsGrep, "grep", "g", "", "Only show lines matching a regular expression")
+	LogsCmd.Flags().BoolVar(&logsErr, "err", false, "Show the autostart error log (server.err)")
+	LogsCmd.Flags().StringVar(&logsFile, "file", "", "Read a specific log file")
+}
+
+func showLogs() error {
+	filter, err := buildLogFilter()
+	if err != nil {
+		return exitcode.UsageError(err)
+	}
+
+	path := logsFile
+	if path == "" {
+		var method string
+		if path, method, err = serverLogFile(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "📝 %s (%s)\n", path, method)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return exitcode.NotFoundError(fmt.Errorf("log file not found: %s", path))
+		}
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	last, err := printTail(f, filter)
+	if err != nil {
+		return fmt.Errorf("failed to read log file: %w", err)
+	}
+
+	if !logsFollow {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return followLog(ctx, path, f, filter, last)
+}
+
+func buildLogFilter() (*logFilter, error) {
+	filter := &logFilter{}
+
+	if logsSince != "" {
+		since, err := parseSince(logsSince, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		filter.since = since
+	}
+
+	if logsLevel != "" {
+		level, err := parseLevel(logsLevel)
+		if err != nil {
+			return nil, err
+		}
+		filter.level = level
+	}
+
+	if logsGrep != "" {
+		re, err := regexp.Compile(logsGrep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		filter.grep = re
+	}
+
+	return filter, nil
+}
+
+// serverLogFile returns the log of the launch method in use, and a description of it.
+// A running server started by 'service start' wins, then an installed autostart unit;
+// otherwise the most recently written log is used.
+func serverLogFile() (string, string, error) {
+	ltDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+
+	startLog := filepath.Join(ltDir, startLogFile)
+	autostartLog := filepath.Join(ltDir, autostartLogFile)
+	if logsErr {
+		autostartLog = filepath.Join(ltDir, autostartErrFile)
+	}
+
+	if running, _, _ := checkProcessRunning(); running && !logsErr {
+		return startLog, "started with 'litetable service start'", nil
+	}
+	if autostartInstalled() {
+		return autostartLog, "autostart", nil
+	}
+
+	// Nothing is running; show whichever log was written last
+	var newest string
+	var newestTime time.Time
+	candidates := []string{startLog, autostartLog}
+	if logsErr {
+		candidates = []string{autostartLog}
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(newestTime) {
+			newest, newestTime = path, info.ModTime()
+		}
+	}
+	if newest == "" {
+		return "", "", exitcode.NotFoundError(fmt.Errorf("no server logs found in %s", ltDir))
+	}
+
+	return newest, "most recently written", nil
+}
+
+// autostartInstalled reports whether 'service init --autostart' installed a launch unit
+func autostartInstalled() bool {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+
+	var unit string
+	switch runtime.GOOS {
+	case "linux":
+		unit = filepath.Join(homeDir, ".config", "systemd", "user", "litetable-server.service")
+	case "darwin":
+		unit = filepath.Join(homeDir, "Library", "LaunchAgents", "com.litetable.server.plist")
+	default:
+		return false
+	}
+
+	_, err = os.Stat(unit)
+	return err == nil
+}
+
+// printTail prints the last matching lines of r (all of them when -n is 0) and returns
+// the last line read, so following can continue its time and level.
+func printTail(r io.Reader, filter *logFilter) (*logLine, error) {
+	var (
+		ring []string
+		next int
+		prev *logLine
+	)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := parseLogLine(scanner.Text(), prev)
+		prev = &line
+		if !filter.match(&line) {
+			continue
+		}
+
+		if logsLines == 0 {
+			fmt.Println(line.text)
+			continue
+		}
+		if len(ring) < logsLines {
+			ring = append(ring, line.text)
+		} else {
+			ring[next] = line.text
+			next = (next + 1) % logsLines
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range ring {
+		fmt.Println(ring[(next+i)%len(ring)])
+	}
+	return prev, nil
+}
+
+// followLog prints lines appended to the log until ctx is cancelled. A file that shrinks
+// or is replaced (for example by rotation) is reopened and read from the start.
+func followLog(ctx context.Context, path string, f *os.File, filter *logFilter, prev *logLine) error {
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	// f may be replaced below, so close whichever file is open last
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var partial []byte
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// Drain the open file first so lines written just before a rotation are not lost
+		for {
+			n, err := f.ReadAt(buf, offset)
+			if n > 0 {
+				offset += int64(n)
+				partial = append(partial, buf[:n]...)
+				for {
+					i := bytes.IndexByte(partial, '\n')
+					if i < 0 {
+						break
+					}
+					line := parseLogLine(string(partial[:i]), prev)
+					prev = &line
+					partial = partial[i+1:]
+					if filter.match(&line) {
+						fmt.Println(line.text)
+					}
+				}
+			}
+			if err == io.EOF || n == 0 {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		current, err := f.Stat()
+		if err != nil {
+			return err
+		}
+
+		if info, err := os.Stat(path); err == nil && (!os.SameFile(info, current) || info.Size() < offset) {
+			reopened, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			_ = f.Close()
+			f, offset, partial = reopened, 0, nil
+			fmt.Fprintf(os.Stderr, "📝 %s was rotated, following the new file\n", path)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/logrotate"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// LogWriterCmd copies the server output from stdin into a rotating log. It is started by
+// 'service start' when log_supervisor is enabled and exits when the server closes its end
+// of the pipe.
+var LogWriterCmd = &cobra.Command{
+	Use:    "logwriter <file>",
+	Short:  "Write server output to a rotating log",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogWriter(args[0])
+	},
+}
+
+func runLogWriter(path string) error {
+	// Outlive the terminal that started the server; stop only when the server's output ends
+	signal.Ignore(os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	cfg, err := logrotate.LoadConfig()
+	if err != nil {
+		cfg = logrotate.DefaultConfig()
+	}
+
+	w, err := logrotate.NewWriter(path, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer w.Close()
+
+	// Write whole lines so a line never spans two segments
+	reader := bufio.NewReaderSize(os.Stdin, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if _, werr := w.Write(line); werr != nil {
+				// Rotation problems must not block the server, so report and carry on
+				fmt.Fprintf(os.Stderr, "⚠️  log rotation: %v\n", werr)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read server output: %w", err)
+		}
+	}
+}
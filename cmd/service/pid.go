@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// pidFileName records the PID of a server launched by 'service start' or 'service run'
+const pidFileName = "litetable.pid"
+
+func pidFilePath() (string, error) {
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+	return filepath.Join(liteTableDir, pidFileName), nil
+}
+
+// removeStalePIDFile deletes a PID file whose process is gone or is not the server
+func removeStalePIDFile(pid int) {
+	pidFile, err := pidFilePath()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(pidFile); err == nil {
+		fmt.Printf("🧹 Removed stale PID file (process %d is not a running LiteTable server)\n", pid)
+	}
+}
+
+// processAlive reports whether a process exists and has not exited. An exited process
+// that has not been reaped yet still accepts signal 0, so zombies are checked on Linux.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Unix systems, FindProcess always succeeds, so we need to send signal 0 to check
+	// if the process exists. On Windows, FindProcess only succeeds if the process exists.
+	if err := process.Signal(syscall.Signal(0)); err != nil {
+		return false
+	}
+
+	if runtime.GOOS == "linux" {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err == nil {
+			if end := bytes.LastIndexByte(stat, ')'); end >= 0 && end+2 < len(stat) && stat[end+2] == 'Z' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isServerProcess reports whether pid runs the LiteTable server, so a PID reused by an
+// unrelated process is not mistaken for it. The command line is read from /proc on Linux
+// and from ps on macOS; elsewhere, or when it cannot be read, the PID is trusted.
+func isServerProcess(pid int) bool {
+	var args []string
+	switch runtime.GOOS {
+	case "linux":
+		cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+		if err != nil || len(cmdline) == 0 {
+			return true
+		}
+		args = strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+	case "darwin":
+		out, err := exec.Command("ps", "-o", "command=", "-p", fmt.Sprint(pid)).Output()
+		if err != nil {
+			return true
+		}
+		args = strings.Fields(string(out))
+	default:
+		return true
+	}
+
+	names := serverBinaryNames()
+	// The binary is usually the first argument, but comes later when run through an
+	// interpreter or a wrapper script
+	for _, arg := range args {
+		if names[strings.TrimSuffix(filepath.Base(arg), ".exe")] {
+			return true
+		}
+	}
+	return false
+}
+
+// serverBinaryNames returns the file names the server may run under: the default binary
+// and any server_binary set in litetable.conf.
+func serverBinaryNames() map[string]bool {
+	names := map[string]bool{serverBin: true}
+
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return names
+	}
+	f, err := config.LoadFile(filepath.Join(liteTableDir, config.FileName))
+	if err != nil {
+		return names
+	}
+	if value, ok := f.Get(config.ServerBinary); ok && value != "" {
+		names[strings.TrimSuffix(filepath.Base(value), ".exe")] = true
+	}
+	return names
+}
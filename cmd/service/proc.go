@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the USER_HZ used by /proc/<pid>/stat. It is 100 on every Linux platform Go
+// supports, and reading it properly would need cgo.
+const clockTicks = 100
+
+// procStats is what /proc reveals about a running process
+type procStats struct {
+	startedAt  time.Time
+	rssBytes   int64
+	cpuSeconds float64
+}
+
+// readProcStats reads the start time, resident memory and CPU time of a process from /proc
+func readProcStats(pid int) (*procStats, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return nil, err
+	}
+
+	// The command name is in parentheses and may contain spaces, so split after it
+	end := strings.LastIndexByte(string(stat), ')')
+	if end < 0 {
+		return nil, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(string(stat)[end+1:])
+	// fields[0] is the state (field 3), so field n is at index n-3
+	if len(fields) < 20 {
+		return nil, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+	}
+
+	utime, _ := strconv.ParseFloat(fields[11], 64)
+	stime, _ := strconv.ParseFloat(fields[12], 64)
+	startTicks, _ := strconv.ParseInt(fields[19], 10, 64)
+
+	bootTime, err := readBootTime()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &procStats{
+		startedAt:  bootTime.Add(time.Duration(startTicks) * time.Second / clockTicks),
+		cpuSeconds: (utime + stime) / clockTicks,
+	}
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if value, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+			kb, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+			s.rssBytes = kb * 1024
+			break
+		}
+	}
+
+	return s, nil
+}
+
+// readBootTime returns when the system booted, from the btime line of /proc/stat
+func readBootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid btime in /proc/stat: %w", err)
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("btime not found in /proc/stat")
+}
+
+// listeningPorts returns the TCP ports in the LISTEN state, mapped to whether the given
+// process owns the socket.
+func listeningPorts(pid int) (map[int]bool, error) {
+	owned := socketInodes(pid)
+
+	ports := make(map[int]bool)
+	found := false
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		found = true
+
+		lines := strings.Split(string(data), "\n")
+		for _, line := range lines[1:] {
+			fields := strings.Fields(line)
+			// local_address is field 1, st is field 3 and inode is field 9
+			if len(fields) < 10 || fields[3] != "0A" {
+				continue
+			}
+
+			_, hexPort, ok := strings.Cut(fields[1], ":")
+			if !ok {
+				continue
+			}
+			port, err := strconv.ParseInt(hexPort, 16, 32)
+			if err != nil {
+				continue
+			}
+
+			ports[int(port)] = ports[int(port)] || owned[fields[9]]
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("/proc/net/tcp is not available")
+	}
+	return ports, nil
+}
+
+// socketInodes returns the inodes of the sockets a process has open. Reading another
+// user's file descriptors is not permitted, in which case the result is empty.
+func socketInodes(pid int) map[string]bool {
+	inodes := make(map[string]bool)
+
+	fdDir := fmt.Sprintf("/proc/%d/fd", pid)
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return inodes
+	}
+
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(fdDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if inode, ok := strings.CutPrefix(link, "socket:["); ok {
+			inodes[strings.TrimSuffix(inode, "]")] = true
+		}
+	}
+
+	return inodes
+}
+
+// dirSize returns the total size of the regular files below path
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// formatBytes renders a size with a binary unit, e.g. 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
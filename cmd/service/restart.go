@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/spf13/cobra"
+	"net"
+	"os"
+	"time"
+)
+
+// portPollInterval is how often a port is probed while waiting for it to be released
+const portPollInterval = 200 * time.Millisecond
+
+var (
+	restartPortTimeout time.Duration
+
+	// RestartCmd represents the restart command
+	RestartCmd = &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the LiteTable server",
+		Long: "Stops the running server, waits until its ports are released and starts it again. " +
+			"A server that is not running is simply started.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restartLiteTable()
+		},
+	}
+)
+
+func init() {
+	RestartCmd.Flags().DurationVar(&stopTimeout, "timeout", 10*time.Second,
+		"How long to wait for a graceful shutdown")
+	RestartCmd.Flags().BoolVar(&stopForce, "force", false,
+		"Kill the server with SIGKILL if it does not stop within --timeout")
+	RestartCmd.Flags().BoolVar(&startWait, "wait", true, "Wait until the restarted server is ready")
+	RestartCmd.Flags().DurationVar(&restartPortTimeout, "port-timeout", 30*time.Second,
+		"How long to wait for the server ports to be released")
+}
+
+func restartLiteTable() error {
+	running, pid, err := checkProcessRunning()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not determine if server is running: %v\n", err)
+	}
+
+	if running {
+		if err := stopLiteTable(); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("ℹ️  LiteTable server is not running.")
+		if pid != 0 {
+			removeStalePIDFile(pid)
+		}
+	}
+
+	target, err := profile.LocalTarget()
+	if err != nil {
+		return fmt.Errorf("failed to read server configuration: %w", err)
+	}
+
+	// A new server cannot bind while the old one, or its sockets, still hold the ports
+	for _, port := range []string{target.RPCPort, target.HTTPPort} {
+		if port == "" {
+			continue
+		}
+		address := net.JoinHostPort(target.Address, port)
+		if err := waitForPortFree(address, restartPortTimeout); err != nil {
+			return err
+		}
+	}
+
+	return startLiteTable()
+}
+
+// waitForPortFree polls until address can be bound, or the timeout passes
+func waitForPortFree(address string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	announced := false
+
+	for {
+		l, err := net.Listen("tcp", address)
+		if err == nil {
+			_ = l.Close()
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("port %s is still in use after %s: %w", address, timeout, err)
+		}
+		if !announced {
+			fmt.Printf("⏳  Waiting for %s to be released...\n", address)
+			announced = true
+		}
+		time.Sleep(portPollInterval)
+	}
+}
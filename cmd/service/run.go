@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/logrotate"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+var (
+	runNoLogFile bool
+
+	// RunCmd represents the run command
+	RunCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Run the LiteTable server in the foreground",
+		Long: "Runs the server attached to the terminal and streams its output. Ctrl-C (SIGINT) " +
+			"and SIGTERM are forwarded to the server as SIGTERM for a graceful shutdown; a second " +
+			"Ctrl-C kills it. The output is also appended to litetable.log, rotated as configured, " +
+			"unless --no-log-file is set.\n\n" +
+			"This is the mode to use for debugging and inside containers, where the CLI can run " +
+			"as PID 1.",
+		Example: "litetable service run\n\nlitetable service run --no-log-file",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLiteTable()
+		},
+	}
+)
+
+func init() {
+	RunCmd.Flags().BoolVar(&runNoLogFile, "no-log-file", false, "Only stream the output, do not write litetable.log")
+}
+
+func runLiteTable() error {
+	if running, pid, _ := checkProcessRunning(); running {
+		return fmt.Errorf("LiteTable server is already running with PID %d, stop it first", pid)
+	} else if pid != 0 {
+		removeStalePIDFile(pid)
+	}
+
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+
+	binPath, err := configuredBinary(liteTableDir, stableBinaryPath(liteTableDir))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(binPath); os.IsNotExist(err) {
+		return exitcode.NotFoundError(fmt.Errorf("server not installed. Run 'litetable service init' to install"))
+	}
+
+	output := io.Writer(os.Stdout)
+	if !runNoLogFile {
+		logFile := filepath.Join(liteTableDir, startLogFile)
+		rotation, err := logrotate.LoadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: %v, using default log rotation settings\n", err)
+		}
+		if _, err := logrotate.RotateIfNeeded(logFile, rotation); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not rotate log file: %v\n", err)
+		}
+
+		logWriter, err := logrotate.NewWriter(logFile, rotation)
+		if err != nil {
+			return fmt.Errorf("failed to create log file: %w", err)
+		}
+		defer logWriter.Close()
+
+		fmt.Printf("📝 Logs are also written to: %s\n", logFile)
+		output = io.MultiWriter(os.Stdout, logWriter)
+	}
+
+	// Merge stdout and stderr into one pipe so lines are copied whole and in order
+	r, w, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	defer r.Close()
+
+	serverCmd := exec.Command(binPath)
+	serverCmd.Stdout = w
+	serverCmd.Stderr = w
+	// Keep the server out of the terminal's process group so Ctrl-C reaches only the CLI,
+	// which then asks the server to shut down gracefully
+	serverCmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+
+	// Register before starting so a signal arriving in between is not lost
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	fmt.Printf("📡 Running LiteTable server from: %s\n", binPath)
+	if err := serverCmd.Start(); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+	_ = w.Close()
+
+	pid := serverCmd.Process.Pid
+	pidFile := filepath.Join(liteTableDir, pidFileName)
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: failed to write PID file: %v\n", err)
+	}
+	defer os.Remove(pidFile)
+
+	fmt.Printf("✅  LiteTable server started with PID: %d (Ctrl-C to stop)\n", pid)
+
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				_, _ = output.Write(line)
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- serverCmd.Wait()
+	}()
+
+	stopping := false
+	for {
+		select {
+		case sig := <-signals:
+			if stopping && sig == os.Interrupt {
+				fmt.Fprintln(os.Stderr, "\n⚠️  Killing LiteTable server...")
+				_ = serverCmd.Process.Kill()
+				continue
+			}
+			if !stopping {
+				fmt.Fprintf(os.Stderr, "\n📩 Received %s, sending graceful shutdown signal to process %d "+
+					"(Ctrl-C again to kill)...\n", sig, pid)
+				stopping = true
+			}
+			_ = serverCmd.Process.Signal(syscall.SIGTERM)
+
+		case err := <-exited:
+			// Wait for the output to be flushed before reporting
+			<-copied
+			return serverExit(err, stopping)
+		}
+	}
+}
+
+// serverExit converts the result of waiting for the foreground server into the CLI result
+func serverExit(err error, stopping bool) error {
+	if err == nil {
+		fmt.Println("✅  LiteTable server has been stopped.")
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return fmt.Errorf("failed to wait for server: %w", err)
+	}
+
+	// Ending on the SIGTERM we forwarded is a normal shutdown
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && stopping &&
+		status.Signaled() && status.Signal() == syscall.SIGTERM {
+		fmt.Println("✅  LiteTable server has been stopped.")
+		return nil
+	}
+
+	return exitcode.New(exitcode.Server, fmt.Errorf("LiteTable server exited: %w", err))
+}
@@ -2,29 +2,44 @@ package service
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/litetable/litetable-cli/internal/config"
 	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/logrotate"
+	"github.com/litetable/litetable-cli/internal/profile"
 	"github.com/spf13/cobra"
 )
 
-var StartCommand = &cobra.Command{
-	Use:   "start",
-	Short: "Start the LiteTable server",
-	Long:  "Start the LiteTable server if installed, otherwise prompt to run init",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := startLiteTable(); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
-	},
+var (
+	startWait    bool
+	startTimeout time.Duration
+
+	StartCommand = &cobra.Command{
+		Use:   "start",
+		Short: "Start the LiteTable server",
+		Long: "Start the LiteTable server if installed, otherwise prompt to run init. By default the " +
+			"command returns once the server answers on its /health endpoint and gRPC API; " +
+			"--wait=false returns as soon as the process is launched.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return startLiteTable()
+		},
+	}
+)
+
+func init() {
+	StartCommand.Flags().BoolVar(&startWait, "wait", true, "Wait until the server is ready")
+	StartCommand.Flags().DurationVar(&startTimeout, "timeout", 30*time.Second,
+		"How long to wait for the server to get ready")
 }
 
 func startLiteTable() error {
@@ -33,10 +48,12 @@ func startLiteTable() error {
 	// Check if the server is already running
 	isRunning, pid, err := checkProcessRunning()
 	if err != nil {
-		fmt.Printf("⚠️  Warning: Could not determine if server is running: %v\n", err)
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not determine if server is running: %v\n", err)
 	} else if isRunning {
 		fmt.Println("✅  LiteTable server is already running.")
 		return nil
+	} else if pid != 0 {
+		removeStalePIDFile(pid)
 	}
 
 	// Get LiteTable directory
@@ -46,9 +63,9 @@ func startLiteTable() error {
 	}
 
 	// Check if LiteTable is installed
-	binPath := filepath.Join(liteTableDir, "bin", serverBin)
-	if runtime.GOOS == "windows" {
-		binPath += ".exe"
+	binPath, err := configuredBinary(liteTableDir, stableBinaryPath(liteTableDir))
+	if err != nil {
+		return err
 	}
 
 	if _, err := os.Stat(binPath); os.IsNotExist(err) {
@@ -63,42 +80,26 @@ func startLiteTable() error {
 		if response == "y" || response == "yes" {
 			return initLiteTable()
 		}
-		return fmt.Errorf("server not installed. Run 'litetable-cli init' to install")
+		return exitcode.NotFoundError(fmt.Errorf("server not installed. Run 'litetable service init' to install"))
 	}
 
-	// Read the config file to get server binary location
-	configPath := filepath.Join(liteTableDir, "litetable.conf")
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		// If config doesn't exist, use the default binary path
-		fmt.Println("⚠️ Configuration file not found, using default binary path")
-	} else {
-		// Read the config file to get the server binary path
-		configBytes, err := os.ReadFile(configPath)
-		if err != nil {
-			return fmt.Errorf("failed to read config file: %w", err)
-		}
-
-		// Parse the config file to find server_binary
-		configLines := strings.Split(string(configBytes), "\n")
-		for _, line := range configLines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "server_binary") {
-				parts := strings.SplitN(line, "=", 2)
-				if len(parts) == 2 {
-					binPath = strings.TrimSpace(parts[1])
-				}
-				break
-			}
-		}
+	// Roll over a log that grew too large or old while the server was stopped
+	logFile := filepath.Join(liteTableDir, startLogFile)
+	rotation, err := logrotate.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %v, using default log rotation settings\n", err)
+	}
+	if rotated, err := logrotate.RotateIfNeeded(logFile, rotation); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not rotate log file: %v\n", err)
+	} else if rotated {
+		fmt.Println("🗂️  Rotated the previous log file")
 	}
 
-	// Create a log file
-	logFile := filepath.Join(liteTableDir, "litetable.log")
-	logFileHandle, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to create log file: %w", err)
+	// Remember where this run's output starts, to show it if the server fails to get ready
+	var logOffset int64
+	if info, err := os.Stat(logFile); err == nil {
+		logOffset = info.Size()
 	}
-	defer logFileHandle.Close()
 
 	// Start the server
 	fmt.Printf("📡 Running LiteTable server from: %s\n", binPath)
@@ -106,37 +107,95 @@ func startLiteTable() error {
 
 	// Create a new command to start the server
 	serverCmd := exec.Command(binPath)
-	serverCmd.Stdout = logFileHandle
-	serverCmd.Stderr = logFileHandle
 
 	// Detach the process from the terminal
 	serverCmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 
+	output, err := openServerLog(logFile, rotation)
+	if err != nil {
+		return err
+	}
+	// The server keeps its own copy, the CLI does not write to the log itself
+	defer output.Close()
+	serverCmd.Stdout = output
+	serverCmd.Stderr = output
+
 	if err := serverCmd.Start(); err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
 	pid = serverCmd.Process.Pid
-	pidFile := filepath.Join(liteTableDir, "litetable.pid")
+	pidFile := filepath.Join(liteTableDir, pidFileName)
 	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644); err != nil {
 		return fmt.Errorf("failed to write PID file: %w", err)
 	}
 
-	fmt.Printf("✅  LiteTable server started with PID: %d\n", pid)
+	if !startWait {
+		fmt.Printf("✅  LiteTable server started with PID: %d\n", pid)
+		return nil
+	}
+
+	target, err := profile.LocalTarget()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not read server configuration, not waiting for readiness: %v\n", err)
+		fmt.Printf("✅  LiteTable server started with PID: %d\n", pid)
+		return nil
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- serverCmd.Wait()
+	}()
+
+	fmt.Printf("⏳  Waiting for LiteTable server (PID %d) to get ready...\n", pid)
+	started := time.Now()
+	if err := waitForReady(target, startTimeout, exited); err != nil {
+		if !processAlive(pid) {
+			_ = os.Remove(pidFile)
+		}
+		if rotation.Supervisor {
+			// Give the log writer a moment to write the last lines it received
+			time.Sleep(followInterval)
+		}
+		printLastLogLines(logFile, logOffset, failureLogLines)
+		return exitcode.New(exitcode.Server, err)
+	}
+
+	fmt.Printf("✅  LiteTable server started with PID %d and ready in %s\n", pid,
+		time.Since(started).Round(time.Millisecond))
 	return nil
 }
 
+// configuredBinary returns the server_binary set in litetable.conf, or binPath when the
+// configuration does not set one.
+func configuredBinary(liteTableDir, binPath string) (string, error) {
+	f, err := config.LoadFile(filepath.Join(liteTableDir, config.FileName))
+	if errors.Is(err, config.ErrNotInstalled) {
+		// If config doesn't exist, use the default binary path
+		fmt.Fprintln(os.Stderr, "⚠️ Configuration file not found, using default binary path")
+		return binPath, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if value, ok := f.Get(config.ServerBinary); ok && value != "" {
+		binPath = value
+	}
+	return binPath, nil
+}
+
+// checkProcessRunning reads the PID file and reports whether that process is a running
+// LiteTable server. The PID is returned even when it is stale.
 func checkProcessRunning() (bool, int, error) {
-	// Get LiteTable directory
-	liteTableDir, err := dir.GetLitetableDir()
+	pidFile, err := pidFilePath()
 	if err != nil {
-		return false, 0, fmt.Errorf("failed to get LiteTable directory: %w", err)
+		return false, 0, err
 	}
 
 	// Check if PID file exists
-	pidFile := filepath.Join(liteTableDir, "litetable.pid")
 	pidBytes, err := os.ReadFile(pidFile)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -151,17 +210,55 @@ func checkProcessRunning() (bool, int, error) {
 		return false, 0, fmt.Errorf("failed to parse PID: %w", err)
 	}
 
-	// Check if process is running
-	process, err := os.FindProcess(pid)
+	// A PID reused by another process after the server exited does not count
+	return processAlive(pid) && isServerProcess(pid), pid, nil
+}
+
+// openServerLog returns the file the server output is written to. With log_supervisor
+// enabled that is a pipe to 'litetable service logwriter', which rotates the log while the
+// server runs; otherwise the log itself is opened and only rotated on start.
+func openServerLog(logFile string, rotation logrotate.Config) (*os.File, error) {
+	if rotation.Supervisor {
+		output, err := startLogWriter(logFile)
+		if err == nil {
+			return output, nil
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: Could not start the log writer, rotating on start only: %v\n", err)
+	}
+
+	output, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return false, pid, fmt.Errorf("failed to find process: %w", err)
+		return nil, fmt.Errorf("failed to create log file: %w", err)
 	}
+	return output, nil
+}
 
-	// On Unix systems, FindProcess always succeeds, so we need to send signal 0 to check
-	// if the process exists. On Windows, FindProcess only succeeds if the process exists.
-	if err := process.Signal(syscall.Signal(0)); err != nil {
-		return false, pid, nil // Process doesn't exist
+// startLogWriter launches the log writer in the background and returns the write end of
+// its input pipe. The writer exits once every copy of that end is closed, i.e. when the
+// server stops (or fails to start).
+func startLogWriter(logFile string) (*os.File, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the litetable executable: %w", err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	defer r.Close()
+
+	writerCmd := exec.Command(self, "service", "logwriter", logFile)
+	writerCmd.Stdin = r
+	writerCmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+	if err := writerCmd.Start(); err != nil {
+		_ = w.Close()
+		return nil, err
 	}
 
-	return true, pid, nil // Process exists
+	// Nobody waits for the writer; release it so it is not left as a zombie of this process
+	_ = writerCmd.Process.Release()
+	return w, nil
 }
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/spf13/cobra"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// cpuSampleInterval is how long CPU usage is measured for
+const cpuSampleInterval = 250 * time.Millisecond
+
+var (
+	statusJSON bool
+
+	// StatusCmd represents the status command
+	StatusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Show a runtime report of the local LiteTable server",
+		Long: "Reports whether the server process is running, its uptime, memory and CPU usage, " +
+			"which configured ports are listening, the installed version, the size of the data " +
+			"directories and the response of the /health endpoint.\n\n" +
+			"Exits with 0 when the server is running and healthy, 4 when it is not running and " +
+			"5 when it is running but unhealthy.",
+		Example: "litetable service status\n\nlitetable service status --json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serverStatus()
+		},
+	}
+)
+
+func init() {
+	StatusCmd.Flags().BoolVar(&statusJSON, "json", false, "Print the report as JSON")
+}
+
+// statusReport is the combined runtime state of the local server
+type statusReport struct {
+	Running       bool         `json:"running"`
+	PID           int          `json:"pid,omitempty"`
+	StalePIDFile  bool         `json:"stale_pid_file,omitempty"`
+	StartedAt     *time.Time   `json:"started_at,omitempty"`
+	UptimeSeconds int64        `json:"uptime_seconds,omitempty"`
+	RSSBytes      int64        `json:"rss_bytes,omitempty"`
+	CPUPercent    float64      `json:"cpu_percent"`
+	CPUSeconds    float64      `json:"cpu_seconds,omitempty"`
+	Version       string       `json:"version,omitempty"`
+	Ports         []portStatus `json:"ports"`
+	DataDirs      []dirUsage   `json:"data_dirs"`
+	DataBytes     int64        `json:"data_bytes"`
+	Health        healthStatus `json:"health"`
+	Warnings      []string     `json:"warnings,omitempty"`
+}
+
+// portStatus compares a configured port with the sockets listening on the machine
+type portStatus struct {
+	Key       string `json:"key"`
+	Port      int    `json:"port"`
+	Listening bool   `json:"listening"`
+	// Server is true when the listening socket belongs to the server process
+	Server bool `json:"server"`
+}
+
+type dirUsage struct {
+	Name  string `json:"name"`
+	Bytes int64  `json:"bytes"`
+}
+
+type healthStatus struct {
+	OK         bool   `json:"ok"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Body       string `json:"body,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func serverStatus() error {
+	report := collectStatus()
+
+	if statusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printStatus(report)
+	}
+
+	if !report.Running {
+		return exitcode.NotFoundError(fmt.Errorf("LiteTable server is not running"))
+	}
+	if !report.Health.OK {
+		return exitcode.New(exitcode.Server, fmt.Errorf("LiteTable server is running but not healthy"))
+	}
+	return nil
+}
+
+// collectStatus gathers everything it can; parts that cannot be determined are recorded
+// as warnings instead of failing the report.
+func collectStatus() *statusReport {
+	report := &statusReport{Ports: []portStatus{}, DataDirs: []dirUsage{}}
+	warn := func(format string, a ...any) {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(format, a...))
+	}
+
+	running, pid, err := checkProcessRunning()
+	if err != nil {
+		warn("could not read PID file: %v", err)
+	}
+	report.Running, report.PID = running, pid
+	report.StalePIDFile = pid != 0 && !running
+
+	if running {
+		if runtime.GOOS == "linux" {
+			collectProcStats(report, warn)
+		} else {
+			warn("process details are only available on Linux")
+		}
+	}
+
+	if version, err := config.Get(config.ServerVersion); err == nil {
+		report.Version = version
+	} else {
+		warn("installed version unknown: %v", err)
+	}
+
+	target, err := profile.LocalTarget()
+	if err != nil {
+		warn("could not read server configuration: %v", err)
+	} else {
+		collectPorts(report, target, warn)
+
+		if target.HTTPPort == "" {
+			report.Health.Error = "no server_port configured"
+		} else if code, body, err := fetchHealth(target); err != nil {
+			report.Health.Error = err.Error()
+		} else {
+			report.Health = healthStatus{OK: code == http.StatusOK, StatusCode: code, Body: body}
+		}
+	}
+
+	collectDataDirs(report, warn)
+	return report
+}
+
+func collectProcStats(report *statusReport, warn func(string, ...any)) {
+	first, err := readProcStats(report.PID)
+	if err != nil {
+		warn("could not read process details: %v", err)
+		return
+	}
+
+	// Sample twice to measure current CPU usage rather than the lifetime average
+	time.Sleep(cpuSampleInterval)
+	second, err := readProcStats(report.PID)
+	if err != nil {
+		second = first
+	}
+
+	startedAt := second.startedAt.UTC()
+	report.StartedAt = &startedAt
+	report.UptimeSeconds = int64(time.Since(startedAt).Seconds())
+	report.RSSBytes = second.rssBytes
+	report.CPUSeconds = second.cpuSeconds
+	report.CPUPercent = (second.cpuSeconds - first.cpuSeconds) / cpuSampleInterval.Seconds() * 100
+}
+
+func collectPorts(report *statusReport, target *profile.Target, warn func(string, ...any)) {
+	var listening map[int]bool
+	if runtime.GOOS == "linux" {
+		var err error
+		if listening, err = listeningPorts(report.PID); err != nil {
+			warn("could not list listening ports: %v", err)
+		}
+	}
+
+	configured := []struct{ key, value string }{
+		{config.ServerPort, target.HTTPPort},
+		{config.ServerRPCPort, target.RPCPort},
+	}
+	for _, c := range configured {
+		port, err := strconv.Atoi(c.value)
+		if err != nil {
+			warn("%s is not configured", c.key)
+			continue
+		}
+
+		status := portStatus{Key: c.key, Port: port}
+		if owned, ok := listening[port]; ok {
+			status.Listening = true
+			status.Server = owned && report.Running
+		}
+		if report.Running && listening != nil && !status.Listening {
+			warn("%s %d is not listening", c.key, port)
+		}
+		report.Ports = append(report.Ports, status)
+	}
+}
+
+func collectDataDirs(report *statusReport, warn func(string, ...any)) {
+	ltDir, err := dir.GetLitetableDir()
+	if err != nil {
+		warn("%v", err)
+		return
+	}
+
+	entries, err := os.ReadDir(ltDir)
+	if err != nil {
+		warn("could not read %s: %v", ltDir, err)
+		return
+	}
+
+	for _, e := range entries {
+		// bin holds the installed server versions, which are not database data
+		if e.IsDir() && e.Name() == "bin" {
+			continue
+		}
+
+		var size int64
+		if e.IsDir() {
+			if size, err = dirSize(filepath.Join(ltDir, e.Name())); err != nil {
+				warn("could not size %s: %v", e.Name(), err)
+				continue
+			}
+		} else if info, err := e.Info(); err == nil && info.Mode().IsRegular() {
+			size = info.Size()
+		} else {
+			continue
+		}
+
+		// Files are only counted in the total, directories are listed individually
+		if e.IsDir() {
+			report.DataDirs = append(report.DataDirs, dirUsage{Name: e.Name(), Bytes: size})
+		}
+		report.DataBytes += size
+	}
+
+	sort.Slice(report.DataDirs, func(i, j int) bool {
+		return report.DataDirs[i].Name < report.DataDirs[j].Name
+	})
+}
+
+func printStatus(r *statusReport) {
+	switch {
+	case r.Running && r.Health.OK:
+		fmt.Println("✅  LiteTable server is running and healthy")
+	case r.Running:
+		fmt.Println("⚠️  LiteTable server is running but not healthy")
+	default:
+		fmt.Println("⏹️  LiteTable server is not running")
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if r.PID != 0 {
+		pid := strconv.Itoa(r.PID)
+		if r.StalePIDFile {
+			pid += " (stale PID file)"
+		}
+		fmt.Fprintf(tw, "  PID:\t%s\n", pid)
+	}
+	if r.StartedAt != nil {
+		fmt.Fprintf(tw, "  Started:\t%s (up %s)\n", r.StartedAt.Local().Format(time.RFC3339),
+			time.Duration(r.UptimeSeconds)*time.Second)
+	}
+	if r.RSSBytes > 0 {
+		fmt.Fprintf(tw, "  Memory:\t%s RSS\n", formatBytes(r.RSSBytes))
+	}
+	if r.StartedAt != nil {
+		fmt.Fprintf(tw, "  CPU:\t%.1f%% (%.1fs total)\n", r.CPUPercent, r.CPUSeconds)
+	}
+	if r.Version != "" {
+		fmt.Fprintf(tw, "  Version:\t%s\n", r.Version)
+	}
+
+	for i, p := range r.Ports {
+		label := ""
+		if i == 0 {
+			label = "Ports:"
+		}
+		state := "not listening"
+		switch {
+		case p.Server:
+			state = "listening (server process)"
+		case p.Listening:
+			state = "listening"
+		}
+		fmt.Fprintf(tw, "  %s\t%s %d: %s\n", label, p.Key, p.Port, state)
+	}
+
+	for i, d := range r.DataDirs {
+		label := ""
+		if i == 0 {
+			label = "Data:"
+		}
+		fmt.Fprintf(tw, "  %s\t%s: %s\n", label, d.Name, formatBytes(d.Bytes))
+	}
+	label := ""
+	if len(r.DataDirs) == 0 {
+		label = "Data:"
+	}
+	fmt.Fprintf(tw, "  %s\ttotal: %s\n", label, formatBytes(r.DataBytes))
+
+	switch {
+	case r.Health.OK:
+		fmt.Fprintf(tw, "  Health:\t%d %s\n", r.Health.StatusCode, summarize(r.Health.Body))
+	case r.Health.StatusCode != 0:
+		fmt.Fprintf(tw, "  Health:\tstatus %d %s\n", r.Health.StatusCode, summarize(r.Health.Body))
+	default:
+		fmt.Fprintf(tw, "  Health:\tunreachable (%s)\n", r.Health.Error)
+	}
+	_ = tw.Flush()
+
+	for _, w := range r.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", w)
+	}
+}
+
+// summarize shortens a response body to its first line for the text report
+func summarize(body string) string {
+	const maxLen = 120
+	line, _, _ := strings.Cut(body, "\n")
+	if len(line) > maxLen {
+		line = line[:maxLen] + "..."
+	}
+	return line
+}
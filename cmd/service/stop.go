@@ -3,53 +3,57 @@ package service
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
 	"github.com/spf13/cobra"
 )
 
-var StopCommand = &cobra.Command{
-	Use:   "stop",
-	Short: "Stop the running LiteTable server",
-	Long:  "Stop the LiteTable server by sending a SIGTERM signal for graceful shutdown",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := stopLiteTable(); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
-	},
+// killWait is how long to wait for the process to disappear after SIGKILL
+const killWait = 5 * time.Second
+
+var (
+	stopTimeout time.Duration
+	stopForce   bool
+
+	StopCommand = &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running LiteTable server",
+		Long: "Stop the LiteTable server by sending a SIGTERM signal for graceful shutdown. If the " +
+			"server does not exit within --timeout the command fails and leaves it running, unless " +
+			"--force is set, in which case the server is killed with SIGKILL.",
+		Example: "litetable service stop\n\nlitetable service stop --timeout 30s --force",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stopLiteTable()
+		},
+	}
+)
+
+func init() {
+	StopCommand.Flags().DurationVar(&stopTimeout, "timeout", 10*time.Second,
+		"How long to wait for a graceful shutdown")
+	StopCommand.Flags().BoolVar(&stopForce, "force", false,
+		"Kill the server with SIGKILL if it does not stop within --timeout")
 }
 
 func stopLiteTable() error {
 	fmt.Println("⏹️ Stopping LiteTable server...")
 
-	// Get LiteTable directory
-	liteTableDir, err := dir.GetLitetableDir()
+	running, pid, err := checkProcessRunning()
 	if err != nil {
-		return fmt.Errorf("failed to get LiteTable directory: %w", err)
+		return err
 	}
-
-	// Check for a PID file
-	pidFile := filepath.Join(liteTableDir, "litetable.pid")
-	if _, err := os.Stat(pidFile); os.IsNotExist(err) {
-		return fmt.Errorf("no running LiteTable server found")
-	}
-
-	// Read PID from a file
-	pidBytes, err := os.ReadFile(pidFile)
-	if err != nil {
-		return fmt.Errorf("failed to read PID file: %w", err)
+	if !running {
+		if pid != 0 {
+			removeStalePIDFile(pid)
+		}
+		return exitcode.NotFoundError(fmt.Errorf("no running LiteTable server found"))
 	}
 
-	pidStr := strings.TrimSpace(string(pidBytes))
-	pid, err := strconv.Atoi(pidStr)
+	pidFile, err := pidFilePath()
 	if err != nil {
-		return fmt.Errorf("invalid PID in file: %w", err)
+		return err
 	}
 
 	// Find process and send SIGTERM
@@ -62,43 +66,53 @@ func stopLiteTable() error {
 
 	// Send SIGTERM for graceful shutdown
 	if err := process.Signal(syscall.SIGTERM); err != nil {
-		// If sending SIGTERM fails, try to remove the PID file anyway
-		_ = os.Remove(pidFile)
+		if !processAlive(pid) {
+			// The server exited on its own in the meantime
+			_ = os.Remove(pidFile)
+			fmt.Println("✅  LiteTable server has been stopped successfully.")
+			return nil
+		}
 		return fmt.Errorf("failed to send shutdown signal: %w", err)
 	}
 
-	// Wait for a short period to see if a process terminates
 	fmt.Println("⏳  Waiting for server to shut down...")
-
-	// Create a timeout channel
-	timeout := time.After(5 * time.Second)
-	shutdown := make(chan struct{})
-
-	// Check if the process has terminated
-	go func() {
-		for {
-			// Check if the process still exists
-			if err := process.Signal(syscall.Signal(0)); err != nil {
-				// Process has terminated
-				close(shutdown)
-				return
-			}
-			time.Sleep(100 * time.Millisecond)
+	if !waitForExit(pid, stopTimeout) {
+		if !stopForce {
+			// The server is still alive, so its PID file stays
+			return exitcode.New(exitcode.Server, fmt.Errorf(
+				"server (PID %d) did not stop within %s; retry with --timeout or use --force to kill it",
+				pid, stopTimeout))
 		}
-	}()
 
-	// Wait for shutdown or timeout
-	select {
-	case <-shutdown:
+		fmt.Fprintf(os.Stderr, "⚠️  Server did not stop within %s, sending SIGKILL to process %d...\n",
+			stopTimeout, pid)
+		if err := process.Kill(); err != nil && processAlive(pid) {
+			return fmt.Errorf("failed to kill server: %w", err)
+		}
+		if !waitForExit(pid, killWait) {
+			return exitcode.New(exitcode.Server, fmt.Errorf("server (PID %d) is still running after SIGKILL", pid))
+		}
+		fmt.Println("💥 LiteTable server was killed.")
+	} else {
 		fmt.Println("✅  LiteTable server has been stopped successfully.")
-	case <-timeout:
-		fmt.Println("⚠️  Timeout waiting for server to stop.")
 	}
 
 	// Clean up PID file
-	if err := os.Remove(pidFile); err != nil {
+	if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove PID file: %w", err)
 	}
 
 	return nil
 }
+
+// waitForExit polls until the process is gone, reporting false if it outlives the timeout
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
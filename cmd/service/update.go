@@ -3,41 +3,55 @@ package service
 import (
 	"bufio"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
 	"github.com/litetable/litetable-cli/internal/dir"
 	"github.com/litetable/litetable-cli/internal/litetable"
 	"github.com/spf13/cobra"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 )
 
 var UpdateCommand = &cobra.Command{
 	Use:   "update",
 	Short: "Update LiteTable server",
-	Long:  "Check for and install the latest version of LiteTable server",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := updateLiteTable(); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
-		}
+	Long: "Check for and install the latest version of LiteTable server, from a prebuilt " +
+		"release archive when one exists for this platform",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateLiteTable()
 	},
 }
 
+func init() {
+	addInstallFlags(UpdateCommand)
+	UpdateCommand.Flags().StringVar(&installChannel, "channel", "",
+		"Release channel to update from: stable, rc, beta or alpha (default: update_channel or stable)")
+}
+
 func updateLiteTable() error {
 	// Get current version
-	currentVersion, err := litetable.GetFromConfig(litetable.ServerVersionKey)
+	currentVersion, err := config.Get(config.ServerVersion)
 	if err != nil {
 		return fmt.Errorf("failed to get current version: %w", err)
 	}
 
+	// Update from where the server was installed from
+	switch method, _ := config.Get(config.InstallMethod); method {
+	case originCheckout:
+		source, _ := config.Get(config.InstallSource)
+		return fmt.Errorf("server was built from the local checkout %s; "+
+			"run 'litetable service init --source %s' to rebuild it", source, source)
+	case originRelease, originGit:
+		if source, err := config.Get(config.InstallSource); err == nil {
+			serverRepo = source
+		}
+	}
+
 	fmt.Printf("Current version: %s\n", currentVersion)
 	fmt.Println("Checking for updates...")
 
-	// Get the latest version from git repository
-	latestVersion, err := litetable.GetLatestVersion(serverRepo)
+	// Get the latest version, or the version of --archive
+	latestVersion, err := serverVersionToInstall()
 	if err != nil {
 		return fmt.Errorf("failed to check for latest version: %w", err)
 	}
@@ -70,73 +84,47 @@ func updateLiteTable() error {
 		return fmt.Errorf("failed to get LiteTable directory: %w", err)
 	}
 
-	// Stop server if running
-	if isServerRunning(liteTableDir) {
-		fmt.Println("Stopping running LiteTable server...")
-		if err := stopLiteTable(); err != nil {
-			return fmt.Errorf("failed to stop server: %w", err)
-		}
-	}
-
-	// Create temporary directory for cloning
-	tempDir, err := ioutil.TempDir("", "litetable-update")
+	// Install next to the current version, which stays available for 'service rollback'.
+	// The running server keeps serving until the new version is downloaded and verified.
+	binPath, err := versionBinaryPath(liteTableDir, latestVersion)
 	if err != nil {
-		return fmt.Errorf("failed to create temp directory: %w", err)
+		return err
 	}
-	defer os.RemoveAll(tempDir)
-
-	// Clone the repository
-	fmt.Println("📥 Downloading latest LiteTable server...")
-	cloneCmd := exec.Command("git", "clone", "--depth", "1", "-b", latestVersion, serverRepo, tempDir)
-	cloneCmd.Stdout = os.Stdout
-	cloneCmd.Stderr = os.Stderr
-	if err := cloneCmd.Run(); err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
-	}
-
-	// Build the server binary
-	fmt.Println("🔨 Building server...")
-	binDir := filepath.Join(liteTableDir, "bin")
-	if err := os.MkdirAll(binDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
 		return fmt.Errorf("failed to create bin directory: %w", err)
 	}
 
-	binPath := filepath.Join(binDir, serverBin)
-	if runtime.GOOS == "windows" {
-		binPath += ".exe"
-	}
-
-	buildCmd := exec.Command("go", "build", "-o", binPath)
-	buildCmd.Env = append(os.Environ(),
-		fmt.Sprintf("GOOS=%s", runtime.GOOS),
-		fmt.Sprintf("GOARCH=%s", runtime.GOARCH))
-	buildCmd.Dir = tempDir
-	buildCmd.Stdout = os.Stdout
-	buildCmd.Stderr = os.Stderr
-	if err := buildCmd.Run(); err != nil {
-		return fmt.Errorf("failed to build server: %w", err)
+	origin, err := installServer(latestVersion, binPath)
+	if err != nil {
+		return err
 	}
 
-	// Make executable (for Unix systems)
-	if runtime.GOOS != "windows" {
-		if err := os.Chmod(binPath, 0755); err != nil {
-			return fmt.Errorf("failed to make server executable: %w", err)
+	// Stop server if running
+	running := isServerRunning()
+	if running {
+		fmt.Println("Stopping running LiteTable server...")
+		if err := stopLiteTable(); err != nil {
+			return fmt.Errorf("failed to stop server: %w", err)
 		}
 	}
 
-	if err = litetable.UpdateConfigValue(&litetable.UpdateConfig{
-		Key:   litetable.ServerVersionKey,
-		Value: latestVersion,
-	}); err != nil {
-		return fmt.Errorf("failed to update config: %w", err)
+	if err := recordOrigin(origin); err != nil {
+		return err
+	}
+	if err := activateVersion(liteTableDir, latestVersion); err != nil {
+		return err
 	}
 
 	fmt.Printf("✅  Successfully updated to LiteTable version %s!\n", latestVersion)
+	fmt.Printf("Run 'litetable service rollback' to switch back to %s.\n", currentVersion)
+
+	if running {
+		return startLiteTable()
+	}
 	return nil
 }
 
-func isServerRunning(liteTableDir string) bool {
-	pidFile := filepath.Join(liteTableDir, "litetable.pid")
-	_, err := os.Stat(pidFile)
-	return err == nil
+func isServerRunning() bool {
+	running, _, _ := checkProcessRunning()
+	return running
 }
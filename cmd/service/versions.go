@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// versionsDirName holds one directory per installed server version, below bin
+const versionsDirName = "versions"
+
+var (
+	// VersionsCmd represents the versions command
+	VersionsCmd = &cobra.Command{
+		Use:   "versions",
+		Short: "List the installed LiteTable server versions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listVersions()
+		},
+	}
+
+	// UseCmd represents the use command
+	UseCmd = &cobra.Command{
+		Use:   "use <version>",
+		Short: "Switch to another installed LiteTable server version",
+		Long: "Makes an installed server version the active one. A running server is stopped " +
+			"and started again with the selected version.",
+		Example: "litetable service use v1.3.2",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return switchVersion(args[0])
+		},
+	}
+
+	// RollbackCmd represents the rollback command
+	RollbackCmd = &cobra.Command{
+		Use:   "rollback",
+		Short: "Switch back to the previously active LiteTable server version",
+		Long: "Switches to the server version that was active before the last install, update or " +
+			"'service use'. Running rollback again switches forward.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			previous, err := config.Get(config.PreviousServerVersion)
+			if err != nil {
+				return exitcode.NotFoundError(fmt.Errorf("no previous server version to roll back to"))
+			}
+			fmt.Printf("⏪ Rolling back to %s\n", previous)
+			return switchVersion(previous)
+		},
+	}
+)
+
+// installedVersion is a server binary below bin/versions
+type installedVersion struct {
+	name        string
+	path        string
+	size        int64
+	installedAt time.Time
+}
+
+// stableBinaryPath is bin/litetable-server. With versioned installs it links to the active
+// version, so autostart units keep working after switching versions.
+func stableBinaryPath(liteTableDir string) string {
+	binPath := filepath.Join(liteTableDir, "bin", serverBin)
+	if runtime.GOOS == "windows" {
+		binPath += ".exe"
+	}
+	return binPath
+}
+
+// versionBinaryPath returns where a version of the server is installed. Branch names may
+// contain slashes, which are replaced in the directory name on every platform. Versions
+// that would not name a directory inside bin/versions, such as "..", are rejected.
+func versionBinaryPath(liteTableDir, version string) (string, error) {
+	dirName := strings.NewReplacer("/", "_", `\`, "_").Replace(version)
+	if dirName == "" || dirName == "." || dirName == ".." {
+		return "", exitcode.UsageError(fmt.Errorf("invalid server version %q", version))
+	}
+
+	name := serverBin
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join(liteTableDir, "bin", versionsDirName, dirName, name), nil
+}
+
+// installedVersions returns the installed versions, most recently installed first
+func installedVersions(liteTableDir string) ([]installedVersion, error) {
+	entries, err := os.ReadDir(filepath.Join(liteTableDir, "bin", versionsDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read installed versions: %w", err)
+	}
+
+	var versions []installedVersion
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path, err := versionBinaryPath(liteTableDir, e.Name())
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, installedVersion{
+			name:        e.Name(),
+			path:        path,
+			size:        info.Size(),
+			installedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].installedAt.After(versions[j].installedAt)
+	})
+	return versions, nil
+}
+
+func listVersions() error {
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+
+	versions, err := installedVersions(liteTableDir)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		return exitcode.NotFoundError(fmt.Errorf("no versioned server installs found; " +
+			"run 'litetable service init' or 'litetable service update'"))
+	}
+
+	active, _ := configuredBinary(liteTableDir, stableBinaryPath(liteTableDir))
+	previous, _ := config.Get(config.PreviousServerVersion)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT\tVERSION\tINSTALLED\tSIZE\t")
+	for _, v := range versions {
+		current := ""
+		if v.path == active {
+			current = "*"
+		}
+		note := ""
+		if strings.ReplaceAll(previous, "/", "_") == v.name {
+			note = "(rollback target)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", current, v.name,
+			v.installedAt.Local().Format("2006-01-02 15:04"), formatBytes(v.size), note)
+	}
+	return tw.Flush()
+}
+
+// switchVersion activates an installed version, restarting the server if it is running
+func switchVersion(version string) error {
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+
+	path, err := versionBinaryPath(liteTableDir, version)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return exitcode.NotFoundError(fmt.Errorf(
+			"version %s is not installed; see 'litetable service versions'", version))
+	}
+
+	if current, _ := config.Get(config.ServerVersion); current == version {
+		fmt.Printf("✅  Already using LiteTable server %s\n", version)
+		return nil
+	}
+
+	running := isServerRunning()
+	if running {
+		if err := stopLiteTable(); err != nil {
+			return fmt.Errorf("failed to stop server: %w", err)
+		}
+	}
+
+	if err := activateVersion(liteTableDir, version); err != nil {
+		return err
+	}
+	fmt.Printf("✅  Now using LiteTable server %s\n", version)
+
+	if running {
+		return startLiteTable()
+	}
+	return nil
+}
+
+// activateVersion points server_binary and the stable binary path at an installed
+// version, remembering the version it replaces for rollback.
+func activateVersion(liteTableDir, version string) error {
+	current, _ := config.Get(config.ServerVersion)
+	if err := adoptLegacyBinary(liteTableDir, current); err != nil {
+		return err
+	}
+
+	f, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	path, err := versionBinaryPath(liteTableDir, version)
+	if err != nil {
+		return err
+	}
+	values := [][2]string{
+		{config.ServerBinary, path},
+		{config.ServerVersion, version},
+	}
+	if current != "" && current != version {
+		values = append(values, [2]string{config.PreviousServerVersion, current})
+	}
+	for _, v := range values {
+		if err := f.Set(v[0], v[1]); err != nil {
+			return fmt.Errorf("failed to update config: %w", err)
+		}
+	}
+	if err := f.Save(); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	return linkStableBinary(liteTableDir, path)
+}
+
+// adoptLegacyBinary moves a server installed before versioned installs, a regular file
+// at the stable path, into the versions directory so it can be rolled back to.
+func adoptLegacyBinary(liteTableDir, version string) error {
+	stable := stableBinaryPath(liteTableDir)
+	info, err := os.Lstat(stable)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	if version == "" {
+		version = "legacy"
+	}
+	target, err := versionBinaryPath(liteTableDir, version)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err == nil {
+		// Already installed side by side; the stable path only needs to become a link
+		return os.Remove(stable)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create version directory: %w", err)
+	}
+	if err := os.Rename(stable, target); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", stable, target, err)
+	}
+	fmt.Printf("📦 Moved the installed server %s to %s\n", version, target)
+	return nil
+}
+
+// linkStableBinary makes the stable binary path a symlink to target. Windows is skipped
+// as creating symlinks there needs elevated privileges.
+func linkStableBinary(liteTableDir, target string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	stable := stableBinaryPath(liteTableDir)
+	tmp := stable + ".link"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("failed to link %s: %w", stable, err)
+	}
+	if err := os.Rename(tmp, stable); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to link %s: %w", stable, err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/litetable/litetable-cli/internal/server"
+	"github.com/spf13/cobra"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	readyPollInterval = 250 * time.Millisecond
+	pingTimeout       = 2 * time.Second
+
+	// failureLogLines is how much of the log is shown when the server does not get ready
+	failureLogLines = 20
+)
+
+var (
+	waitTimeout time.Duration
+
+	// WaitCmd represents the wait command
+	WaitCmd = &cobra.Command{
+		Use:   "wait",
+		Short: "Wait until the LiteTable server is ready",
+		Long: "Polls the /health endpoint and the gRPC API of the server selected by the active " +
+			"context until both answer. Exits with 0 once the server is ready, or 3 when it is " +
+			"not ready within --timeout. Useful in scripts and CI setup steps.",
+		Example: "litetable service wait --timeout 60s\n\nlitetable service wait --server 10.0.0.5:49786",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := profile.Resolve()
+			if err != nil {
+				return exitcode.ConnectionError(err)
+			}
+
+			fmt.Printf("⏳  Waiting for LiteTable server at %s...\n", target.RPCAddress())
+			start := time.Now()
+			if err := waitForReady(target, waitTimeout, nil); err != nil {
+				return exitcode.ConnectionError(err)
+			}
+			fmt.Printf("✅  LiteTable server is ready (%s)\n", time.Since(start).Round(time.Millisecond))
+			return nil
+		},
+	}
+)
+
+func init() {
+	WaitCmd.Flags().DurationVar(&waitTimeout, "timeout", 30*time.Second, "How long to wait for the server")
+}
+
+// waitForReady polls the server until both its /health endpoint and its gRPC API answer,
+// or the timeout passes. exited, if not nil, reports the server process ending, which
+// stops the wait early.
+func waitForReady(target *profile.Target, timeout time.Duration, exited <-chan error) error {
+	client, err := server.NewClientForTarget(target)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		lastErr := probeReady(target, client)
+		if lastErr == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server not ready after %s: %w", timeout, lastErr)
+		}
+
+		select {
+		case err := <-exited:
+			if err == nil {
+				return fmt.Errorf("server exited before becoming ready")
+			}
+			return fmt.Errorf("server exited before becoming ready: %w", err)
+		case <-time.After(readyPollInterval):
+		}
+	}
+}
+
+// probeReady returns why the server is not ready yet, or nil when it is
+func probeReady(target *profile.Target, client *server.GrpcClient) error {
+	if target.HTTPPort != "" {
+		code, body, err := fetchHealth(target)
+		if err != nil {
+			return fmt.Errorf("health check failed: %w", err)
+		}
+		if code != http.StatusOK {
+			return fmt.Errorf("health check returned status %d: %s", code, summarize(body))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		return fmt.Errorf("gRPC API not answering: %w", err)
+	}
+	return nil
+}
+
+// printLastLogLines shows the end of the log to explain why the server did not get ready.
+// Only lines after offset, i.e. written by the server just started, are shown.
+func printLastLogLines(path string, offset int64, n int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	// A log that was rotated in the meantime is shown from the start
+	if info, err := f.Stat(); err == nil && info.Size() >= offset {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return
+		}
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "📝 Last lines of %s:\n", path)
+	for _, line := range lines {
+		fmt.Fprintf(os.Stderr, "    %s\n", line)
+	}
+}
@@ -0,0 +1,435 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"github.com/litetable/litetable-cli/internal/output"
+	"github.com/litetable/litetable-cli/internal/server"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// command is a single shell command
+type command struct {
+	usage string
+	help  string
+	// family is true when the first argument is a column family, so it can be completed
+	family bool
+	// timed commands report their duration after running
+	timed bool
+	run   func(s *session, args []string) error
+}
+
+var commands map[string]*command
+
+func init() {
+	commands = map[string]*command{
+		"get": {
+			usage:  "get <family> <key> [qualifier ...] [latest=N]",
+			help:   "Read a single row",
+			family: true,
+			timed:  true,
+			run:    (*session).get,
+		},
+		"scan": {
+			usage:  "scan <family> [prefix=P | regex=R] [latest=N] [limit=N]",
+			help:   "Read every row matching a prefix or regex (all rows by default)",
+			family: true,
+			timed:  true,
+			run:    (*session).scan,
+		},
+		"put": {
+			usage:  "put <family> <key> <qualifier>=<value> ...",
+			help:   "Write qualifier values to a row",
+			family: true,
+			timed:  true,
+			run:    (*session).put,
+		},
+		"del": {
+			usage:  "del <family> <key> [qualifier ...]",
+			help:   "Delete a row, or only the given qualifiers",
+			family: true,
+			timed:  true,
+			run:    (*session).del,
+		},
+		"create": {
+			usage: "create <family> [family ...]",
+			help:  "Create column families",
+			timed: true,
+			run:   (*session).create,
+		},
+		"families": {
+			usage: "families",
+			help:  "List the known column families",
+			run:   (*session).listFamilies,
+		},
+		"output": {
+			usage: "output [text|json|ndjson|yaml|csv|table]",
+			help:  "Show or change how rows are printed",
+			run:   (*session).setOutput,
+		},
+		"help": {
+			usage: "help",
+			help:  "Show this help",
+			run:   (*session).help,
+		},
+		"exit": {usage: "exit", help: "Leave the shell"},
+		"quit": {usage: "quit", help: "Leave the shell"},
+	}
+}
+
+func (s *session) get(args []string) error {
+	opts, args, err := parseOptions(args, "latest")
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		return usage("get")
+	}
+
+	latest, err := intOption(opts, "latest")
+	if err != nil {
+		return err
+	}
+
+	return s.read(&server.ReadParams{
+		Key:        args[1],
+		QueryType:  server.Read,
+		Family:     args[0],
+		Qualifiers: args[2:],
+		Latest:     int32(latest),
+	}, 0)
+}
+
+func (s *session) scan(args []string) error {
+	opts, args, err := parseOptions(args, "prefix", "regex", "latest", "limit")
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usage("scan")
+	}
+
+	_, hasPrefix := opts["prefix"]
+	_, hasRegex := opts["regex"]
+	if hasPrefix && hasRegex {
+		return argErrorf("only one of prefix= or regex= can be given")
+	}
+
+	latest, err := intOption(opts, "latest")
+	if err != nil {
+		return err
+	}
+	limit, err := intOption(opts, "limit")
+	if err != nil {
+		return err
+	}
+
+	params := &server.ReadParams{
+		Key:       ".*",
+		QueryType: server.ReadRegex,
+		Family:    args[0],
+		Latest:    int32(latest),
+	}
+	if hasPrefix {
+		params.Key, params.QueryType = opts["prefix"], server.ReadPrefix
+	}
+	if hasRegex {
+		params.Key = opts["regex"]
+	}
+
+	return s.read(params, limit)
+}
+
+// read runs a query and prints the rows. A positive limit keeps only the first rows by key.
+func (s *session) read(params *server.ReadParams, limit int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rows, err := s.client.Read(ctx, params)
+	if err != nil {
+		if errors.Is(err, server.ErrRowNotFound) {
+			fmt.Fprintln(s.out, "0 rows")
+			return nil
+		}
+		return err
+	}
+
+	total := len(rows)
+	if limit > 0 && total > limit {
+		keys := make([]string, 0, total)
+		for key := range rows {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys[limit:] {
+			delete(rows, key)
+		}
+	}
+
+	if err := output.Rows(s.out, s.format, rows); err != nil {
+		return err
+	}
+
+	if len(rows) < total {
+		fmt.Fprintf(s.out, "%d of %d rows\n", len(rows), total)
+	} else {
+		fmt.Fprintf(s.out, "%d %s\n", total, plural(total, "row"))
+	}
+	return nil
+}
+
+func (s *session) put(args []string) error {
+	if len(args) < 3 {
+		return usage("put")
+	}
+
+	params := &server.WriteParams{
+		Key:    args[1],
+		Family: args[0],
+	}
+	for _, arg := range args[2:] {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return argErrorf("expected <qualifier>=<value>, got %q", arg)
+		}
+		params.Qualifiers = append(params.Qualifiers, server.Qualifier{
+			Name:  name,
+			Value: []byte(value),
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	rows, err := s.client.Write(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return output.Rows(s.out, s.format, rows)
+}
+
+func (s *session) del(args []string) error {
+	if len(args) < 2 {
+		return usage("del")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := s.client.Delete(ctx, &server.DeleteParams{
+		Key:        args[1],
+		Family:     args[0],
+		Qualifiers: args[2:],
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.out, "Deleted")
+	return nil
+}
+
+func (s *session) create(args []string) error {
+	if len(args) == 0 {
+		return usage("create")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := s.client.CreateFamilies(ctx, &server.CreateFamilyParams{Families: args}); err != nil {
+		return err
+	}
+
+	// Remember the families so they complete even if the families file is not local
+	for _, family := range args {
+		s.families[family] = true
+	}
+
+	fmt.Fprintf(s.out, "Created %d %s\n", len(args), plural(len(args), "family"))
+	return nil
+}
+
+func (s *session) listFamilies([]string) error {
+	for _, family := range s.knownFamilies() {
+		fmt.Fprintln(s.out, family)
+	}
+	return nil
+}
+
+// knownFamilies returns the families from the families file and those created during
+// the session, sorted and without duplicates.
+func (s *session) knownFamilies() []string {
+	seen := make(map[string]bool, len(s.families))
+	for family := range s.families {
+		seen[family] = true
+	}
+
+	// The families file only exists next to a locally installed server
+	if families, err := dir.GetFamilies(); err == nil {
+		for _, family := range families {
+			seen[family] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for family := range seen {
+		result = append(result, family)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *session) setOutput(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(s.out, s.format)
+		return nil
+	}
+
+	format, err := output.ParseFormat(args[0])
+	if err != nil {
+		return err
+	}
+	s.format = format
+	return nil
+}
+
+func (s *session) help([]string) error {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(s.out, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s\t%s\n", commands[name].usage, commands[name].help)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(s.out, "\nQuote arguments containing spaces: put people p:1 name=\"Jane Doe\"")
+	return nil
+}
+
+// parseOptions separates name=value options from positional arguments. Only the given
+// option names are recognised, so other arguments may contain '='.
+func parseOptions(args []string, names ...string) (map[string]string, []string, error) {
+	opts := make(map[string]string)
+	var rest []string
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		known := false
+		for _, n := range names {
+			if ok && name == n {
+				known = true
+				break
+			}
+		}
+		if !known {
+			rest = append(rest, arg)
+			continue
+		}
+		if _, dup := opts[name]; dup {
+			return nil, nil, argErrorf("%s= given more than once", name)
+		}
+		opts[name] = value
+	}
+	return opts, rest, nil
+}
+
+func intOption(opts map[string]string, name string) (int, error) {
+	value, ok := opts[name]
+	if !ok {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, argErrorf("%s must be a non-negative number, got %q", name, value)
+	}
+	return n, nil
+}
+
+// argError is a mistake in the arguments of a command, found before it ran
+type argError struct {
+	msg string
+}
+
+func (e *argError) Error() string {
+	return e.msg
+}
+
+func argErrorf(format string, a ...any) error {
+	return &argError{msg: fmt.Sprintf(format, a...)}
+}
+
+func usage(name string) error {
+	return argErrorf("usage: %s", commands[name].usage)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return strings.TrimSuffix(noun, "y") + "ies"
+	}
+	return noun + "s"
+}
+
+// splitArgs splits a line into arguments. Single and double quotes group words, and a
+// backslash escapes the next character outside single quotes.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
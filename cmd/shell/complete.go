@@ -0,0 +1,66 @@
+package shell
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/output"
+	"io"
+	"sort"
+	"strings"
+)
+
+// complete handles Tab at pos in line. The first word completes to a command name, the
+// word after a command that takes a family completes to a known family, and the argument
+// of output completes to a format. A unique match is inserted; otherwise the common
+// prefix is inserted, or the candidates are listed when there is nothing to add.
+func (s *session) complete(w io.Writer, line string, pos int) (string, int, bool) {
+	prefix := line[:pos]
+	start := strings.LastIndexAny(prefix, " \t") + 1
+	word := prefix[start:]
+	before := strings.Fields(prefix[:start])
+
+	var candidates []string
+	switch {
+	case len(before) == 0:
+		for name := range commands {
+			candidates = append(candidates, name)
+		}
+	case len(before) == 1 && before[0] == "output":
+		for _, f := range output.Formats {
+			candidates = append(candidates, string(f))
+		}
+	case len(before) == 1 && commands[before[0]] != nil && commands[before[0]].family:
+		candidates = s.knownFamilies()
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return line, pos, true
+	case 1:
+		return insert(line, pos, matches[0][len(word):]+" ")
+	}
+
+	common := matches[0]
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(m, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(common) > len(word) {
+		return insert(line, pos, common[len(word):])
+	}
+
+	fmt.Fprintln(w, strings.Join(matches, "  "))
+	return line, pos, true
+}
+
+func insert(line string, pos int, text string) (string, int, bool) {
+	return line[:pos] + text + line[pos:], pos + len(text), true
+}
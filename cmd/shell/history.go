@@ -0,0 +1,96 @@
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	historyFile = "shell_history"
+	maxHistory  = 1000
+)
+
+// history keeps the lines entered at the prompt and appends each one to the history
+// file, so they can be recalled with the arrow keys in later sessions.
+type history struct {
+	entries []string
+	file    *os.File
+}
+
+// openHistory loads the most recent entries of the history file and opens it for appending
+func openHistory() (*history, error) {
+	ltDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(ltDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create LiteTable directory: %w", err)
+	}
+
+	path := filepath.Join(ltDir, historyFile)
+	h := &history{}
+
+	if existing, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(existing)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				h.entries = append(h.entries, line)
+			}
+		}
+		_ = existing.Close()
+
+		// Keep the file from growing without bound
+		if len(h.entries) > maxHistory {
+			h.entries = h.entries[len(h.entries)-maxHistory:]
+			content := strings.Join(h.entries, "\n") + "\n"
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				return nil, fmt.Errorf("failed to trim history file: %w", err)
+			}
+		}
+	}
+
+	h.file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+
+	return h, nil
+}
+
+// Add records a line, skipping blank lines and repeats of the previous line
+func (h *history) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || strings.ContainsAny(entry, "\r\n") {
+		return
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return
+	}
+
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+
+	_, _ = fmt.Fprintln(h.file, entry)
+}
+
+// Len returns the number of entries
+func (h *history) Len() int {
+	return len(h.entries)
+}
+
+// At returns an entry, where 0 is the most recent one
+func (h *history) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// Close closes the history file
+func (h *history) Close() error {
+	return h.file.Close()
+}
@@ -0,0 +1,216 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/exitcode"
+	"github.com/litetable/litetable-cli/internal/output"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"github.com/litetable/litetable-cli/internal/server"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 30 * time.Second
+
+var (
+	timeout time.Duration
+
+	Command = &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive LiteTable shell",
+		Long: "Opens an interactive shell that keeps a single connection to the server open. " +
+			"Family names complete with Tab, history is kept in ~/.litetable/shell_history and " +
+			"every command reports how long it took. When input is not a terminal, commands " +
+			"are read line by line, which makes the shell usable from scripts.",
+		Example: "litetable shell\n\n" +
+			"echo 'get wrestlers champion:1' | litetable shell",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run()
+		},
+	}
+)
+
+func init() {
+	Command.Flags().DurationVar(&timeout, "timeout", defaultTimeout, "Maximum duration of each command")
+}
+
+// session is the state kept between the commands of a shell
+type session struct {
+	client   *server.GrpcClient
+	out      io.Writer
+	errOut   io.Writer
+	format   output.Format
+	families map[string]bool
+}
+
+func run() error {
+	target, err := profile.Resolve()
+	if err != nil {
+		return exitcode.ConnectionError(fmt.Errorf("failed to create LiteTable client: %w", err))
+	}
+
+	client, err := server.NewClientForTarget(target)
+	if err != nil {
+		return exitcode.ConnectionError(fmt.Errorf("failed to create LiteTable client: %w", err))
+	}
+
+	defer func() {
+		_ = client.Close()
+	}()
+
+	s := &session{
+		client:   client,
+		out:      os.Stdout,
+		errOut:   os.Stderr,
+		format:   output.Text,
+		families: make(map[string]bool),
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return s.runScript(os.Stdin)
+	}
+
+	return s.runInteractive(fd, fmt.Sprintf("litetable:%s> ", target.Name))
+}
+
+// runInteractive reads commands from the terminal with line editing, history and
+// completion until the user exits.
+func (s *session) runInteractive(fd int, prompt string) error {
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to configure terminal: %w", err)
+	}
+
+	defer func() {
+		_ = term.Restore(fd, oldState)
+	}()
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, prompt)
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		_ = t.SetSize(width, height)
+	}
+
+	// The terminal translates newlines for raw mode, so all output goes through it
+	s.out, s.errOut = t, t
+
+	history, err := openHistory()
+	if err != nil {
+		fmt.Fprintf(t, "⚠️ History is disabled: %v\n", err)
+	} else {
+		defer func() {
+			_ = history.Close()
+		}()
+		t.History = history
+	}
+
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return s.complete(t, line, pos)
+	}
+
+	fmt.Fprintln(t, "LiteTable shell. Type 'help' for commands, 'exit' or Ctrl-D to quit.")
+	for {
+		line, err := t.ReadLine()
+		if err == io.EOF {
+			// Writing through the terminal would repaint the prompt
+			fmt.Fprint(os.Stdout, "\r\n")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.execute(line) {
+			return nil
+		}
+	}
+}
+
+// runScript executes one command per line of r, skipping blank lines and # comments.
+// Every line runs even if an earlier one fails.
+func (s *session) runScript(r io.Reader) error {
+	failed := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !s.run(line) {
+			failed++
+		}
+		if isExit(line) {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read commands: %w", err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d command(s) failed", failed)
+	}
+	return nil
+}
+
+// execute runs a line typed at the prompt and reports whether the shell should exit
+func (s *session) execute(line string) bool {
+	s.run(line)
+	return isExit(line)
+}
+
+// run parses and runs a single line, printing errors and the duration of the command.
+// It reports whether the command succeeded.
+func (s *session) run(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		return false
+	}
+	if len(args) == 0 {
+		return true
+	}
+
+	c, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(s.errOut, "Error: unknown command %q. Type 'help' for commands.\n", args[0])
+		return false
+	}
+	if c.run == nil {
+		return true
+	}
+
+	start := time.Now()
+	err = c.run(s, args[1:])
+	elapsed := time.Since(start)
+
+	if err != nil {
+		fmt.Fprintf(s.errOut, "Error: %v\n", err)
+	}
+	// Arguments are checked before anything is sent, so there is nothing to time
+	var argErr *argError
+	if c.timed && !errors.As(err, &argErr) {
+		fmt.Fprintf(s.errOut, "(%s)\n", elapsed.Round(time.Microsecond))
+	}
+	return err == nil
+}
+
+func isExit(line string) bool {
+	args, err := splitArgs(line)
+	return err == nil && len(args) > 0 && (args[0] == "exit" || args[0] == "quit")
+}
@@ -16,11 +16,8 @@ var uninstallCommand = &cobra.Command{
 	Use:   "uninstall",
 	Short: "Uninstall LiteTable CLI",
 	Long:  "Removes the LiteTable CLI binary directory while preserving your data and configuration",
-	Run: func(cmd *cobra.Command, args []string) {
-		if err := uninstallLiteTable(); err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return uninstallLiteTable()
 	},
 }
 
@@ -66,8 +63,8 @@ func uninstallLiteTable() error {
 
 	// Remove LiteTable from PATH in shell config files
 	if err := removeFromPath(liteTableDir); err != nil {
-		fmt.Printf("Warning: Failed to remove LiteTable from PATH: %v\n", err)
-		fmt.Println("You may need to manually remove the LiteTable PATH entry from your shell configuration file.")
+		fmt.Fprintf(os.Stderr, "Warning: Failed to remove LiteTable from PATH: %v\n", err)
+		fmt.Fprintln(os.Stderr, "You may need to manually remove the LiteTable PATH entry from your shell configuration file.")
 	}
 
 	fmt.Println("✅ LiteTable CLI binaries have been successfully removed.")
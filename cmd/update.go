@@ -3,6 +3,8 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/exitcode"
 	"github.com/litetable/litetable-cli/internal/litetable"
 	"github.com/spf13/cobra"
 	"os"
@@ -12,27 +14,40 @@ import (
 )
 
 var (
-	installUrl = litetable.CLIInstallURL
-	UpdateCmd  = &cobra.Command{
+	installUrl    = litetable.CLIInstallURL
+	updateChannel string
+	UpdateCmd     = &cobra.Command{
 		Use:   "update",
 		Short: "Update LiteTable CLI",
 		Long:  "Check for and install the latest version of LiteTable CLI",
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := updateCLI(); err != nil {
-				fmt.Printf("Error: %v\n", err)
-			}
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateCLI()
 		},
 	}
 )
 
+func init() {
+	UpdateCmd.Flags().StringVar(&updateChannel, "channel", "",
+		"Release channel to update from: stable, rc, beta or alpha (default: update_channel or stable)")
+}
+
 func updateCLI() error {
 	// Get current CLI version
 	currentVersion := litetable.CLIVersion
 
+	configured, _ := config.Get(config.UpdateChannel)
+	channel, err := litetable.ResolveChannel(updateChannel, configured)
+	if err != nil {
+		return exitcode.UsageError(err)
+	}
+
 	fmt.Printf("Current CLI version: %s\nChecking for updates...\n", currentVersion)
+	if channel != litetable.ChannelStable {
+		fmt.Printf("Including %s pre-releases\n", channel)
+	}
 
-	// Get the latest CLI version from GitHub API
-	latestVersion, err := litetable.GetLatestVersion(litetable.CLIURL)
+	// Get the latest CLI version in the channel from the repository tags
+	latestVersion, err := litetable.GetLatestVersionInChannel(litetable.CLIURL, channel)
 	if err != nil {
 		return fmt.Errorf("failed to check for latest version: %w", err)
 	}
@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
 	"github.com/litetable/litetable-cli/internal/litetable"
 	"github.com/spf13/cobra"
+	"os"
 )
 
 var (
@@ -13,9 +15,9 @@ var (
 		Long:  "Show the installed version of LiteTable server from configuration",
 		Run: func(cmd *cobra.Command, args []string) {
 			version := "not set"
-			foundVersion, err := litetable.GetFromConfig(litetable.ServerVersionKey)
+			foundVersion, err := config.Get(config.ServerVersion)
 			if err != nil {
-				fmt.Println("\nRun \033[0;33m`litetable service init`\033[0m to configure the server.")
+				fmt.Fprintln(os.Stderr, "\nRun \033[0;33m`litetable service init`\033[0m to configure the server.")
 			} else {
 				version = foundVersion
 			}
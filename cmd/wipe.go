@@ -17,11 +17,8 @@ var (
 		Short: "Wipe all LiteTable data",
 		Long: "Wipe removes all LiteTable data files from the WAL, Garbage Collector, " +
 			"and data backups. Does not remove server configuration.",
-		Run: func(cmd *cobra.Command, args []string) {
-			if err := wipeData(); err != nil {
-				fmt.Printf("Error: %v\n", err)
-				return
-			}
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return wipeData()
 		},
 	}
 )
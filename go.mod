@@ -5,6 +5,7 @@ go 1.24.2
 require (
 	github.com/litetable/litetable-db/pkg v0.0.0-20250512132958-7bb62cea4f71
 	github.com/spf13/cobra v1.9.1
+	golang.org/x/term v0.32.0
 	google.golang.org/grpc v1.72.0
 )
 
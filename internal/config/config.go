@@ -0,0 +1,266 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is the typed content of litetable.conf. Keys missing from the file have their
+// default value.
+type Config struct {
+	ServerBinary          string
+	ServerVersion         string
+	PreviousServerVersion string
+	InstallMethod         string
+	InstallSource         string
+
+	ServerPort             int
+	ServerRPCPort          int
+	ServerAddress          string
+	Debug                  bool
+	GarbageCollectionTimer int
+	BackupTimer            int
+	SnapshotTimer          int
+	MaxSnapshotLimit       int
+	CloudEnvironment       string
+
+	MCPServerEnabled bool
+	MCPServerAddress string
+	MCPServerPort    int
+
+	TLSEnabled            bool
+	TLSCAFile             string
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSServerName         string
+	TLSInsecureSkipVerify bool
+
+	LogMaxSizeMB  int
+	LogMaxAge     time.Duration
+	LogMaxBackups int
+	LogCompress   bool
+	LogSupervisor bool
+
+	ReleasePublicKey string
+	UpdateChannel    string
+}
+
+// invalidWarnings limits the warnings about invalid values to one per key and process,
+// e.g. in the shell, which reads the configuration for every command
+var invalidWarnings sync.Map
+
+// Read loads and decodes litetable.conf. Invalid values fall back to their default with a
+// warning on stderr, so a typo in one key does not break commands that do not use it;
+// 'litetable doctor' lists them with their line numbers.
+func Read() (*Config, error) {
+	f, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	c, invalid := f.Config()
+	for _, err := range invalid {
+		if _, warned := invalidWarnings.LoadOrStore(err.Error(), true); warned {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Warning: %s: %v, using the default\n", FileName, err)
+	}
+	return c, nil
+}
+
+// Config decodes the file, applying defaults to missing keys. Invalid values are replaced
+// by their default and returned as errors.
+func (f *File) Config() (*Config, []error) {
+	c := &Config{}
+	strs := map[string]*string{
+		ServerBinary:          &c.ServerBinary,
+		ServerVersion:         &c.ServerVersion,
+		PreviousServerVersion: &c.PreviousServerVersion,
+		InstallMethod:         &c.InstallMethod,
+		InstallSource:         &c.InstallSource,
+		ServerAddress:         &c.ServerAddress,
+		CloudEnvironment:      &c.CloudEnvironment,
+		MCPServerAddress:      &c.MCPServerAddress,
+		TLSCAFile:             &c.TLSCAFile,
+		TLSCertFile:           &c.TLSCertFile,
+		TLSKeyFile:            &c.TLSKeyFile,
+		TLSServerName:         &c.TLSServerName,
+		ReleasePublicKey:      &c.ReleasePublicKey,
+		UpdateChannel:         &c.UpdateChannel,
+	}
+	ints := map[string]*int{
+		ServerPort:             &c.ServerPort,
+		ServerRPCPort:          &c.ServerRPCPort,
+		GarbageCollectionTimer: &c.GarbageCollectionTimer,
+		BackupTimer:            &c.BackupTimer,
+		SnapshotTimer:          &c.SnapshotTimer,
+		MaxSnapshotLimit:       &c.MaxSnapshotLimit,
+		MCPServerPort:          &c.MCPServerPort,
+		LogMaxSizeMB:           &c.LogMaxSizeMB,
+		LogMaxBackups:          &c.LogMaxBackups,
+	}
+	bools := map[string]*bool{
+		Debug:                 &c.Debug,
+		MCPServerEnabled:      &c.MCPServerEnabled,
+		TLSEnabled:            &c.TLSEnabled,
+		TLSInsecureSkipVerify: &c.TLSInsecureSkipVerify,
+		LogCompress:           &c.LogCompress,
+		LogSupervisor:         &c.LogSupervisor,
+	}
+
+	var invalid []error
+	for _, k := range keys {
+		value, ok := f.Get(k.Name)
+		if !ok || value == "" {
+			value = k.Default
+		} else if err := k.Validate(value); err != nil {
+			invalid = append(invalid, err)
+			value = k.Default
+		}
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case strs[k.Name] != nil:
+			*strs[k.Name] = value
+		case ints[k.Name] != nil:
+			*ints[k.Name], _ = strconv.Atoi(value)
+		case bools[k.Name] != nil:
+			*bools[k.Name], _ = strconv.ParseBool(value)
+		case k.Name == LogMaxAge:
+			c.LogMaxAge, _ = parseAge(value)
+		}
+	}
+	return c, invalid
+}
+
+// IssueKind classifies a problem found in the file
+type IssueKind int
+
+const (
+	// IssueMalformed is a line that is not "key = value"
+	IssueMalformed IssueKind = iota
+	// IssueUnknown is a key the CLI does not know, often a typo
+	IssueUnknown
+	// IssueDuplicate is a key set again; only its first value counts
+	IssueDuplicate
+	// IssueInvalid is a value of the wrong type or out of range
+	IssueInvalid
+	// IssueMissing is a key 'service init' writes that is needed to run the server
+	IssueMissing
+)
+
+// Issue is a problem found in the file. Line is 0 for missing keys.
+type Issue struct {
+	Kind IssueKind
+	Line int
+	Key  string
+	Err  error
+}
+
+func (i Issue) Error() string {
+	if i.Line == 0 {
+		return i.Err.Error()
+	}
+	return fmt.Sprintf("line %d: %v", i.Line, i.Err)
+}
+
+// requiredKeys have no default and are needed to start the server
+var requiredKeys = []string{ServerBinary, ServerVersion}
+
+// Issues reports every problem in the file
+func (f *File) Issues() []Issue {
+	var issues []Issue
+	seen := make(map[string]bool)
+	for i, l := range f.lines {
+		n := i + 1
+		trimmed := strings.TrimSpace(l.text)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case l.key == "":
+			issues = append(issues, Issue{Kind: IssueMalformed, Line: n,
+				Err: fmt.Errorf("expected 'key = value', got %q", trimmed)})
+			continue
+		}
+
+		k, known := Lookup(l.key)
+		switch {
+		case !known:
+			issues = append(issues, Issue{Kind: IssueUnknown, Line: n, Key: l.key,
+				Err: fmt.Errorf("unknown key %q", l.key)})
+		case seen[l.key]:
+			issues = append(issues, Issue{Kind: IssueDuplicate, Line: n, Key: l.key,
+				Err: fmt.Errorf("%s is set more than once; this value is ignored", l.key)})
+		case l.value != "":
+			if err := k.Validate(l.value); err != nil {
+				issues = append(issues, Issue{Kind: IssueInvalid, Line: n, Key: l.key, Err: err})
+			}
+		}
+		seen[l.key] = true
+	}
+
+	for _, key := range requiredKeys {
+		if value, _ := f.Get(key); value == "" {
+			issues = append(issues, Issue{Kind: IssueMissing, Key: key,
+				Err: fmt.Errorf("%s is missing", key)})
+		}
+	}
+	return issues
+}
+
+// Defaults returns the default value of every key that has one
+func Defaults() map[string]string {
+	defaults := make(map[string]string)
+	for _, k := range keys {
+		if k.Default != "" {
+			defaults[k.Name] = k.Default
+		}
+	}
+	return defaults
+}
+
+// sections lays out the file 'service init' writes
+var sections = []struct {
+	header string
+	keys   []string
+}{
+	{"# DO NOT CHANGE THIS FILE MANUALLY", []string{ServerBinary, ServerVersion, InstallMethod, InstallSource}},
+	{"\n########################################\n" +
+		"#### LiteTable Server Configuration ####\n" +
+		"########################################\n",
+		[]string{ServerPort, ServerRPCPort, ServerAddress, Debug, GarbageCollectionTimer, BackupTimer,
+			SnapshotTimer, MaxSnapshotLimit}},
+	{"\n## MCP Server settings", []string{MCPServerEnabled, MCPServerAddress, MCPServerPort}},
+	{"\n## Server log rotation (log_max_age accepts 24h or 7d, 0 disables)",
+		[]string{LogMaxSizeMB, LogMaxAge, LogMaxBackups, LogCompress, LogSupervisor}},
+}
+
+// Generate returns a complete configuration file with the default settings. values
+// provides the keys without a default, such as server_binary, and may override defaults.
+func Generate(values map[string]string) []byte {
+	var b strings.Builder
+	for _, s := range sections {
+		b.WriteString(s.header)
+		b.WriteByte('\n')
+		for _, key := range s.keys {
+			value, ok := values[key]
+			if !ok {
+				k, _ := Lookup(key)
+				value = k.Default
+			}
+			fmt.Fprintf(&b, "%s = %s\n", key, value)
+		}
+	}
+	return []byte(b.String())
+}
+
+// WriteFile writes a generated configuration file to path atomically
+func WriteFile(path string, values map[string]string) error {
+	return writeAtomic(path, Generate(values))
+}
@@ -0,0 +1,220 @@
+// Package config reads and writes litetable.conf, the "key = value" file that configures
+// the local server and the CLI.
+//
+// Lines are kept as written, so comments, blank lines and the order of keys survive an
+// update, and files are replaced atomically.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the configuration file in the LiteTable directory
+const FileName = "litetable.conf"
+
+// ErrNotInstalled is returned when litetable.conf does not exist
+var ErrNotInstalled = errors.New("LiteTable is not installed or configuration file not found")
+
+// Path returns the location of litetable.conf
+func Path() (string, error) {
+	liteTableDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get LiteTable directory: %w", err)
+	}
+	return filepath.Join(liteTableDir, FileName), nil
+}
+
+// line is one line of the file. key is empty for blank lines, comments and malformed lines.
+type line struct {
+	text  string
+	key   string
+	value string
+}
+
+// File is a parsed litetable.conf
+type File struct {
+	path  string
+	lines []line
+}
+
+// Load reads litetable.conf, returning ErrNotInstalled when it does not exist
+func Load() (*File, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads a configuration file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotInstalled
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(path, data), nil
+}
+
+// Parse parses the content of a configuration file that is saved to path
+func Parse(path string, data []byte) *File {
+	f := &File{path: path}
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return f
+	}
+	for _, t := range strings.Split(text, "\n") {
+		f.lines = append(f.lines, parseLine(t))
+	}
+	return f
+}
+
+func parseLine(text string) line {
+	l := line{text: text}
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return l
+	}
+	if key, value, ok := strings.Cut(trimmed, "="); ok {
+		l.key, l.value = strings.TrimSpace(key), strings.TrimSpace(value)
+	}
+	return l
+}
+
+// Get returns the value of key. When a key is set more than once the first value counts.
+func (f *File) Get(key string) (string, bool) {
+	for _, l := range f.lines {
+		if l.key == key {
+			return l.value, true
+		}
+	}
+	return "", false
+}
+
+// Values returns every key set in the file
+func (f *File) Values() map[string]string {
+	values := make(map[string]string)
+	for _, l := range f.lines {
+		if _, seen := values[l.key]; l.key != "" && !seen {
+			values[l.key] = l.value
+		}
+	}
+	return values
+}
+
+// Set validates value and stores it, replacing the line of an existing key in place or
+// appending a new one. Unknown keys are refused.
+func (f *File) Set(key, value string) error {
+	k, ok := Lookup(key)
+	if !ok {
+		return fmt.Errorf("unknown configuration key %q", key)
+	}
+	value = strings.TrimSpace(value)
+	if err := k.Validate(value); err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("%s = %s", key, value)
+	for i, l := range f.lines {
+		if l.key == key {
+			f.lines[i] = line{text: text, key: key, value: value}
+			return nil
+		}
+	}
+	f.lines = append(f.lines, line{text: text, key: key, value: value})
+	return nil
+}
+
+// Unset removes every line setting key and reports whether there was one
+func (f *File) Unset(key string) bool {
+	kept := f.lines[:0]
+	removed := false
+	for _, l := range f.lines {
+		if l.key == key {
+			removed = true
+			continue
+		}
+		kept = append(kept, l)
+	}
+	f.lines = kept
+	return removed
+}
+
+// Bytes returns the file content
+func (f *File) Bytes() []byte {
+	var b strings.Builder
+	for _, l := range f.lines {
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// Save writes the file atomically: the content goes to a temporary file next to it, which
+// then replaces the original, so a crash never leaves a truncated configuration.
+func (f *File) Save() error {
+	return writeAtomic(f.path, f.Bytes())
+}
+
+func writeAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// Get returns the value of key from litetable.conf. A missing or empty key is an error.
+func Get(key string) (string, error) {
+	f, err := Load()
+	if err != nil {
+		return "", err
+	}
+	if value, ok := f.Get(key); ok && value != "" {
+		return value, nil
+	}
+	return "", fmt.Errorf("%s not found in configuration", key)
+}
+
+// Set validates and stores a value in litetable.conf
+func Set(key, value string) error {
+	f, err := Load()
+	if err != nil {
+		return err
+	}
+	if err := f.Set(key, value); err != nil {
+		return err
+	}
+	return f.Save()
+}
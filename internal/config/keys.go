@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of litetable.conf
+const (
+	// Written by 'service init', 'service update' and 'service use'
+	ServerBinary          = "server_binary"
+	ServerVersion         = "server_version"
+	PreviousServerVersion = "previous_server_version"
+	InstallMethod         = "install_method"
+	InstallSource         = "install_source"
+
+	// Server settings
+	ServerPort             = "server_port"
+	ServerRPCPort          = "server_rpc_port"
+	ServerAddress          = "server_address"
+	Debug                  = "debug"
+	GarbageCollectionTimer = "garbage_collection_timer"
+	BackupTimer            = "backup_timer"
+	SnapshotTimer          = "snapshot_timer"
+	MaxSnapshotLimit       = "max_snapshot_limit"
+	CloudEnvironment       = "cloud_environment"
+
+	// MCP server settings
+	MCPServerEnabled = "mcp_server_enabled"
+	MCPServerAddress = "mcp_server_address"
+	MCPServerPort    = "mcp_server_port"
+
+	// TLS settings for the gRPC connection to the server
+	TLSEnabled            = "tls_enabled"
+	TLSCAFile             = "tls_ca_file"
+	TLSCertFile           = "tls_cert_file"
+	TLSKeyFile            = "tls_key_file"
+	TLSServerName         = "tls_server_name"
+	TLSInsecureSkipVerify = "tls_insecure_skip_verify"
+
+	// Rotation of the server log written by 'service start'
+	LogMaxSizeMB  = "log_max_size_mb"
+	LogMaxAge     = "log_max_age"
+	LogMaxBackups = "log_max_backups"
+	LogCompress   = "log_compress"
+	LogSupervisor = "log_supervisor"
+
+	// ReleasePublicKey is the ed25519 key prebuilt server releases must be signed with
+	ReleasePublicKey = "release_public_key"
+	// UpdateChannel is the release channel 'update' and 'service update' install from
+	UpdateChannel = "update_channel"
+)
+
+// Type is the kind of value a key holds
+type Type int
+
+const (
+	TypeString Type = iota
+	TypeBool
+	TypeInt
+	TypePort
+	// TypeAge is a Go duration (12h) or a number of days (7d); 0 disables
+	TypeAge
+	// TypeChannel is a release channel, see litetable.Channels
+	TypeChannel
+)
+
+// Key describes a configuration key
+type Key struct {
+	Name string
+	Type Type
+	// Min and Max bound TypeInt values
+	Min, Max int
+	// Default is the value 'service init' writes; keys without one are optional
+	Default string
+	// Settable keys can be changed with 'litetable config'; the others are managed by the
+	// service commands
+	Settable bool
+}
+
+// keys lists every known key, grouped like the file 'service init' writes
+var keys = []Key{
+	{Name: ServerBinary},
+	{Name: ServerVersion},
+	{Name: InstallMethod},
+	{Name: InstallSource},
+	{Name: PreviousServerVersion},
+
+	{Name: ServerPort, Type: TypePort, Default: "9443", Settable: true},
+	{Name: ServerRPCPort, Type: TypePort, Default: "49786", Settable: true},
+	{Name: ServerAddress, Default: "127.0.0.1", Settable: true},
+	{Name: Debug, Type: TypeBool, Default: "true", Settable: true},
+	{Name: GarbageCollectionTimer, Type: TypeInt, Min: 1, Max: 86400, Default: "60", Settable: true},
+	{Name: BackupTimer, Type: TypeInt, Min: 1, Max: 86400, Default: "80", Settable: true},
+	{Name: SnapshotTimer, Type: TypeInt, Min: 1, Max: 86400, Default: "20", Settable: true},
+	{Name: MaxSnapshotLimit, Type: TypeInt, Min: 1, Max: 1000, Default: "5", Settable: true},
+	{Name: CloudEnvironment, Settable: true},
+
+	{Name: MCPServerEnabled, Type: TypeBool, Default: "false", Settable: true},
+	{Name: MCPServerAddress, Default: "127.0.0.1", Settable: true},
+	{Name: MCPServerPort, Type: TypePort, Default: "49787", Settable: true},
+
+	{Name: LogMaxSizeMB, Type: TypeInt, Min: 0, Max: 1 << 20, Default: "50", Settable: true},
+	{Name: LogMaxAge, Type: TypeAge, Default: "0", Settable: true},
+	{Name: LogMaxBackups, Type: TypeInt, Min: 0, Max: 10000, Default: "5", Settable: true},
+	{Name: LogCompress, Type: TypeBool, Default: "true", Settable: true},
+	{Name: LogSupervisor, Type: TypeBool, Default: "true", Settable: true},
+
+	{Name: TLSEnabled, Type: TypeBool, Settable: true},
+	{Name: TLSCAFile, Settable: true},
+	{Name: TLSCertFile, Settable: true},
+	{Name: TLSKeyFile, Settable: true},
+	{Name: TLSServerName, Settable: true},
+	{Name: TLSInsecureSkipVerify, Type: TypeBool, Settable: true},
+
+	{Name: ReleasePublicKey, Settable: true},
+	{Name: UpdateChannel, Type: TypeChannel, Settable: true},
+}
+
+// Keys returns every known key in file order
+func Keys() []Key {
+	return append([]Key(nil), keys...)
+}
+
+// SettableKeys returns the names of the keys 'litetable config' may change
+func SettableKeys() []string {
+	var names []string
+	for _, k := range keys {
+		if k.Settable {
+			names = append(names, k.Name)
+		}
+	}
+	return names
+}
+
+// Lookup returns the description of a key
+func Lookup(name string) (Key, bool) {
+	for _, k := range keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Validate checks that value is valid for the key
+func (k Key) Validate(value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("invalid %s: value must be a single line", k.Name)
+	}
+
+	switch k.Type {
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid %s %q: expected true or false", k.Name, value)
+		}
+	case TypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < k.Min || n > k.Max {
+			return fmt.Errorf("invalid %s %q: expected a number from %d to %d", k.Name, value, k.Min, k.Max)
+		}
+	case TypePort:
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("invalid %s %q: expected a port from 1 to 65535", k.Name, value)
+		}
+	case TypeAge:
+		if _, err := parseAge(value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", k.Name, value, err)
+		}
+	case TypeChannel:
+		if err := litetable.ValidateChannel(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", k.Name, err)
+		}
+	}
+	return nil
+}
+
+// parseAge accepts a Go duration (12h) or a number of days (7d)
+func parseAge(v string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("expected a duration such as 12h or 7d")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("expected a duration such as 12h or 7d")
+	}
+	return d, nil
+}
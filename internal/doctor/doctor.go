@@ -0,0 +1,67 @@
+// Package doctor runs diagnostics of the LiteTable setup and reports how to fix what
+// it finds.
+package doctor
+
+import (
+	"fmt"
+	"io"
+)
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+)
+
+// Result is the outcome of one check. Fix tells the user how to resolve a warning or
+// failure.
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+// Check runs one diagnostic and reports one or more results
+type Check func() []Result
+
+// OK reports a passed check
+func OK(name, format string, a ...any) Result {
+	return Result{Name: name, Status: StatusOK, Detail: fmt.Sprintf(format, a...)}
+}
+
+// Warn reports a problem that does not stop LiteTable from working
+func Warn(name, detail, fix string) Result {
+	return Result{Name: name, Status: StatusWarn, Detail: detail, Fix: fix}
+}
+
+// Fail reports a problem that has to be fixed
+func Fail(name, detail, fix string) Result {
+	return Result{Name: name, Status: StatusFail, Detail: detail, Fix: fix}
+}
+
+// Run runs the checks in order, prints every result to w and returns the number of
+// failures
+func Run(w io.Writer, checks []Check) int {
+	failures := 0
+	for _, check := range checks {
+		for _, r := range check() {
+			icon := "✅"
+			switch r.Status {
+			case StatusWarn:
+				icon = "⚠️ "
+			case StatusFail:
+				icon = "❌"
+				failures++
+			}
+			fmt.Fprintf(w, "%s %s: %s\n", icon, r.Name, r.Detail)
+			if r.Status != StatusOK && r.Fix != "" {
+				fmt.Fprintf(w, "   → %s\n", r.Fix)
+			}
+		}
+	}
+	return failures
+}
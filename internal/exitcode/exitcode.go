@@ -0,0 +1,93 @@
+package exitcode
+
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes returned by the CLI. They are part of the public interface of the CLI, so
+// existing values must never change meaning.
+const (
+	OK         = 0 // Command completed successfully
+	General    = 1 // Any failure not covered by a more specific code
+	Usage      = 2 // Invalid flags, arguments or configuration values
+	Connection = 3 // The server could not be reached
+	NotFound   = 4 // The requested row, file or process does not exist
+	Server     = 5 // The server received the request but returned an error
+)
+
+// Error attaches an exit code to an error
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New wraps err with the provided exit code. A nil error stays nil.
+func New(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// UsageError marks err as caused by invalid user input
+func UsageError(err error) error {
+	return New(Usage, err)
+}
+
+// ConnectionError marks err as a failure to reach the server
+func ConnectionError(err error) error {
+	return New(Connection, err)
+}
+
+// NotFoundError marks err as a missing resource
+func NotFoundError(err error) error {
+	return New(NotFound, err)
+}
+
+// Code returns the exit code for err. Errors without an explicit code are classified by
+// their gRPC status, falling back to General.
+func Code(err error) int {
+	if err == nil {
+		return OK
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.OK:
+			return OK
+		case codes.Unavailable, codes.DeadlineExceeded:
+			return Connection
+		case codes.NotFound:
+			return NotFound
+		case codes.InvalidArgument:
+			return Usage
+		case codes.Unknown:
+			return General
+		default:
+			return Server
+		}
+	}
+
+	return General
+}
+
+// Classified reports whether err already carries an explicit exit code
+func Classified(err error) bool {
+	var e *Error
+	return errors.As(err, &e)
+}
@@ -7,8 +7,18 @@ import (
 	"strings"
 )
 
-// GetLatestVersion fetches the latest version of a provided git repository URL
+// GetLatestVersion fetches the latest stable version of a provided git repository URL
 func GetLatestVersion(url string) (string, error) {
+	return GetLatestVersionInChannel(url, ChannelStable)
+}
+
+// GetLatestVersionInChannel fetches the highest version tag of a git repository that
+// belongs to a release channel
+func GetLatestVersionInChannel(url, channel string) (string, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return "", err
+	}
+
 	// Use git to list remote tags and get the latest version
 	cmd := exec.Command("git", "ls-remote", "--tags", url)
 	output, err := cmd.Output()
@@ -16,33 +26,41 @@ func GetLatestVersion(url string) (string, error) {
 		return "", fmt.Errorf("failed to fetch remote tags: %w", err)
 	}
 
-	// Parse output to find the latest version tag
-	re := regexp.MustCompile(`refs/tags/(v\d+\.\d+\.\d+)$`)
-	var versions []string
+	// Parse output to find the latest version tag; peeled tags (^{}) are skipped
+	re := regexp.MustCompile(`refs/tags/(v[^\s^]+)$`)
+	var latestTag string
+	var latest Version
 
 	for _, line := range strings.Split(string(output), "\n") {
-		matches := re.FindStringSubmatch(line)
-		if len(matches) > 1 {
-			versions = append(versions, matches[1])
+		matches := re.FindStringSubmatch(strings.TrimSpace(line))
+		if len(matches) < 2 {
+			continue
+		}
+		v, err := ParseVersion(matches[1])
+		if err != nil || !v.InChannel(channel) {
+			continue
+		}
+		if latestTag == "" || v.Compare(latest) > 0 {
+			latestTag, latest = matches[1], v
 		}
 	}
 
-	if len(versions) == 0 {
+	if latestTag == "" {
 		return "", fmt.Errorf("no version tags found")
 	}
-
-	// Sort versions and return the latest
-	// For simplicity, we'll rely on string comparison which works for vX.Y.Z format
-	latestVersion := versions[0]
-	for _, v := range versions[1:] {
-		if strings.Compare(v, latestVersion) > 0 {
-			latestVersion = v
-		}
-	}
-
-	return latestVersion, nil
+	return latestTag, nil
 }
 
+// IsUpdateAvailable reports whether latestVersion is newer than currentVersion. Versions
+// that are not semantic versions, such as branch names, fall back to a string comparison.
 func IsUpdateAvailable(currentVersion, latestVersion string) bool {
-	return strings.Compare(latestVersion, currentVersion) > 0
+	current, err := ParseVersion(currentVersion)
+	if err != nil {
+		return strings.Compare(latestVersion, currentVersion) > 0
+	}
+	latest, err := ParseVersion(latestVersion)
+	if err != nil {
+		return strings.Compare(latestVersion, currentVersion) > 0
+	}
+	return latest.Compare(current) > 0
 }
@@ -0,0 +1,87 @@
+package litetable
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Record is one row of one family in the fixture format used by test_data/*.json and the
+// dashboard JSON upload:
+//
+//	{"rowkey": "car:1", "family": "cars", "qualifiers": {"brand": "Ford"}}
+//
+// Exports that include every version emit one record per version with its Timestamp set.
+// The timestamp is informational; the server assigns new timestamps when records are
+// imported, in the order they appear.
+type Record struct {
+	RowKey     string         `json:"rowkey"`
+	Family     string         `json:"family"`
+	Qualifiers map[string]any `json:"qualifiers"`
+	Timestamp  int64          `json:"timestamp,omitempty"`
+}
+
+// Validate checks that the record can be written to the server
+func (r *Record) Validate() error {
+	if r.RowKey == "" {
+		return fmt.Errorf("rowkey is required")
+	}
+	if r.Family == "" {
+		return fmt.Errorf("family is required for rowkey %q", r.RowKey)
+	}
+	if len(r.Qualifiers) == 0 {
+		return fmt.Errorf("at least one qualifier is required for rowkey %q", r.RowKey)
+	}
+	return nil
+}
+
+// DecodeRecords reads records from either a JSON array or newline-delimited JSON.
+// Numbers are kept as json.Number so they are written exactly as they appear.
+func DecodeRecords(r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+
+	// Peek at the first non-whitespace byte to tell an array apart from NDJSON
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.ContainsRune([]byte(" \t\r\n"), rune(b)) {
+			first = b
+			_ = br.UnreadByte()
+			break
+		}
+	}
+
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+
+	if first == '[' {
+		var records []Record
+		if err := dec.Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON array: %w", err)
+		}
+		return records, nil
+	}
+
+	var records []Record
+	for i := 1; ; i++ {
+		var rec Record
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode record %d: %w", i, err)
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,230 @@
+package litetable
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Release channels select which pre-releases an update may install. Each channel also
+// accepts every more stable one, e.g. beta accepts rc and stable releases.
+const (
+	ChannelStable = "stable"
+	ChannelRC     = "rc"
+	ChannelBeta   = "beta"
+	ChannelAlpha  = "alpha"
+)
+
+// Channels lists the release channels from most to least stable
+var Channels = []string{ChannelStable, ChannelRC, ChannelBeta, ChannelAlpha}
+
+// Version is a semantic version (https://semver.org), optionally prefixed with "v"
+type Version struct {
+	Major, Minor, Patch uint64
+	// Prerelease holds the dot separated identifiers after "-", e.g. [rc 1] for v1.2.0-rc.1
+	Prerelease []string
+	// Build is the metadata after "+"; it does not take part in comparisons
+	Build string
+}
+
+// ParseVersion parses a version such as v1.2.3, 1.10.0-rc.1 or v2.0.0-beta+exp.sha.5114f85
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build = rest[i+1:]
+		rest = rest[:i]
+		if !validIdentifiers(v.Build, false) {
+			return Version{}, fmt.Errorf("invalid version %q: bad build metadata", s)
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		pre := rest[i+1:]
+		rest = rest[:i]
+		if !validIdentifiers(pre, true) {
+			return Version{}, fmt.Errorf("invalid version %q: bad pre-release", s)
+		}
+		v.Prerelease = strings.Split(pre, ".")
+	}
+
+	parts := strings.Split(rest, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("invalid version %q: expected MAJOR.MINOR.PATCH", s)
+	}
+	nums := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		if !isNumeric(p) || (len(p) > 1 && p[0] == '0') {
+			return Version{}, fmt.Errorf("invalid version %q: bad number %q", s, p)
+		}
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// validIdentifiers checks dot separated [0-9A-Za-z-] identifiers. Numeric pre-release
+// identifiers must not have leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	if s == "" {
+		return false
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, c := range id {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return false
+			}
+		}
+		if prerelease && isNumeric(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the version with a "v" prefix
+func (v Version) String() string {
+	s := fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// IsPrerelease reports whether the version has a pre-release part
+func (v Version) IsPrerelease() bool {
+	return len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or higher than o, following
+// semver precedence: a pre-release is lower than its release and build metadata is ignored.
+func (v Version) Compare(o Version) int {
+	for _, c := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(o.Prerelease); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.Prerelease) < len(o.Prerelease):
+		return -1
+	case len(v.Prerelease) > len(o.Prerelease):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier compares numeric identifiers numerically and others in ASCII order;
+// numeric identifiers sort below alphanumeric ones.
+func compareIdentifier(a, b string) int {
+	an, bn := isNumeric(a), isNumeric(b)
+	switch {
+	case an && bn:
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// ValidateChannel returns an error for an unknown release channel
+func ValidateChannel(channel string) error {
+	for _, c := range Channels {
+		if channel == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown release channel %q, expected one of: %s", channel,
+		strings.Join(Channels, ", "))
+}
+
+// ResolveChannel returns channel when set, else the configured update_channel, else the
+// stable channel
+func ResolveChannel(channel, configured string) (string, error) {
+	if channel == "" {
+		channel = configured
+	}
+	if channel == "" {
+		return ChannelStable, nil
+	}
+	if err := ValidateChannel(channel); err != nil {
+		return "", err
+	}
+	return channel, nil
+}
+
+// InChannel reports whether the version may be installed from channel. Pre-releases are
+// ranked by the label of their first identifier (rc1, beta.2, ...); unknown labels only
+// appear on the alpha channel.
+func (v Version) InChannel(channel string) bool {
+	return stability(v) <= channelRank(channel)
+}
+
+func channelRank(channel string) int {
+	for i, c := range Channels {
+		if channel == c {
+			return i
+		}
+	}
+	return 0
+}
+
+func stability(v Version) int {
+	if !v.IsPrerelease() {
+		return 0
+	}
+	label := strings.ToLower(strings.TrimRight(v.Prerelease[0], "0123456789-"))
+	switch label {
+	case ChannelRC:
+		return 1
+	case ChannelBeta:
+		return 2
+	}
+	return 3
+}
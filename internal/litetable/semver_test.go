@@ -0,0 +1,207 @@
+package litetable
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "v1.2.3", want: "v1.2.3"},
+		{in: "1.10.0", want: "v1.10.0"},
+		{in: " v0.0.0 ", want: "v0.0.0"},
+		{in: "v1.5.0-rc.1", want: "v1.5.0-rc.1"},
+		{in: "v2.0.0-beta+exp.sha.5114f85", want: "v2.0.0-beta+exp.sha.5114f85"},
+		{in: "v1.0.0-0a.1", want: "v1.0.0-0a.1"},
+		{in: "v1.0.0-rc.0", want: "v1.0.0-rc.0"},
+
+		{in: "v01.2.3", wantErr: true},
+		{in: "v1.02.3", wantErr: true},
+		{in: "v1.2.03", wantErr: true},
+		{in: "v1.2.3-rc.01", wantErr: true},
+		{in: "v1.2", wantErr: true},
+		{in: "v1.2.3.4", wantErr: true},
+		{in: "v1.x.3", wantErr: true},
+		{in: "v1.2.3-", wantErr: true},
+		{in: "v1.2.3-rc..1", wantErr: true},
+		{in: "v1.2.3+", wantErr: true},
+		{in: "v1.2.3-rc_1", wantErr: true},
+		{in: "main", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			v, err := ParseVersion(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVersion(%q) = %s, want an error", tt.in, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseVersion(%q) returned error: %v", tt.in, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("ParseVersion(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v0.10.0", "v0.9.0", 1},
+		{"v1.0.0", "v1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"v1.2.3", "v1.2.4", -1},
+		{"v2.0.0", "v1.99.99", 1},
+
+		// A pre-release sorts below its release
+		{"v1.5.0-rc.2", "v1.5.0", -1},
+		{"v1.5.0", "v1.5.0-alpha", 1},
+		{"v1.5.0-rc.1", "v1.4.9", 1},
+
+		// Pre-release identifiers
+		{"v1.0.0-rc.2", "v1.0.0-rc.10", -1},
+		{"v1.0.0-1", "v1.0.0-alpha", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.beta", "v1.0.0-beta", -1},
+		{"v1.0.0-beta.11", "v1.0.0-rc.1", -1},
+
+		// Build metadata is ignored
+		{"v1.0.0+build.1", "v1.0.0+build.2", 0},
+		{"v1.0.0-rc.1+a", "v1.0.0-rc.1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			a, err := ParseVersion(tt.a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ParseVersion(tt.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.Compare(b); got != tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := b.Compare(a); got != -tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionInChannel(t *testing.T) {
+	tests := []struct {
+		version string
+		channel string
+		want    bool
+	}{
+		{"v1.5.0", ChannelStable, true},
+		{"v1.5.0", ChannelAlpha, true},
+		{"v1.5.0+build.7", ChannelStable, true},
+
+		{"v1.5.0-rc.1", ChannelStable, false},
+		{"v1.5.0-rc.1", ChannelRC, true},
+		{"v1.5.0-rc1", ChannelRC, true},
+		{"v1.5.0-RC.1", ChannelRC, true},
+		{"v1.5.0-rc.1", ChannelBeta, true},
+
+		{"v1.5.0-beta.2", ChannelRC, false},
+		{"v1.5.0-beta.2", ChannelBeta, true},
+		{"v1.5.0-beta.2", ChannelAlpha, true},
+
+		{"v1.5.0-alpha.1", ChannelBeta, false},
+		{"v1.5.0-alpha.1", ChannelAlpha, true},
+		{"v1.5.0-nightly", ChannelBeta, false},
+		{"v1.5.0-nightly", ChannelAlpha, true},
+
+		// Unknown channels only accept stable releases
+		{"v1.5.0", "nightly", true},
+		{"v1.5.0-rc.1", "nightly", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+"_"+tt.channel, func(t *testing.T) {
+			v, err := ParseVersion(tt.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := v.InChannel(tt.channel); got != tt.want {
+				t.Errorf("%s.InChannel(%q) = %v, want %v", tt.version, tt.channel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveChannel(t *testing.T) {
+	tests := []struct {
+		name       string
+		channel    string
+		configured string
+		want       string
+		wantErr    bool
+	}{
+		{name: "default", want: ChannelStable},
+		{name: "configured", configured: ChannelBeta, want: ChannelBeta},
+		{name: "flag", channel: ChannelRC, want: ChannelRC},
+		{name: "flag overrides config", channel: ChannelAlpha, configured: ChannelRC, want: ChannelAlpha},
+		{name: "unknown flag", channel: "nightly", wantErr: true},
+		{name: "unknown config", configured: "nightly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveChannel(tt.channel, tt.configured)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ResolveChannel(%q, %q) = %q, want an error", tt.channel, tt.configured, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveChannel(%q, %q) returned error: %v", tt.channel, tt.configured, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveChannel(%q, %q) = %q, want %q", tt.channel, tt.configured, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUpdateAvailable(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.0.0", "v1.1.0", true},
+		{"v0.9.0", "v0.10.0", true},
+		{"v0.10.0", "v0.9.0", false},
+		{"v1.1.0", "v1.1.0", false},
+		{"v1.5.0-rc.2", "v1.5.0", true},
+		{"v1.5.0", "v1.5.0-rc.2", false},
+		{"v1.5.0-rc.2", "v1.5.0-rc.10", true},
+		{"v1.5.0+build.1", "v1.5.0+build.2", false},
+
+		// Versions that are not semver fall back to comparing the strings
+		{"main", "v1.0.0", true},
+		{"v1.0.0", "main", false},
+		{"dev", "dev", false},
+		{"", "v1.0.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"_"+tt.latest, func(t *testing.T) {
+			if got := IsUpdateAvailable(tt.current, tt.latest); got != tt.want {
+				t.Errorf("IsUpdateAvailable(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"net/url"
+	"time"
 )
 
 const (
@@ -54,6 +54,15 @@ func (tv *TimestampedValue) GetString() string {
 	return string(tv.Value)
 }
 
+// Time converts the timestamp to a time.Time. The server reports nanoseconds, but
+// second-precision timestamps are accepted as well.
+func (tv *TimestampedValue) Time() time.Time {
+	if tv.Timestamp > 1e15 || tv.Timestamp < -1e15 {
+		return time.Unix(0, tv.Timestamp).UTC()
+	}
+	return time.Unix(tv.Timestamp, 0).UTC()
+}
+
 // VersionedQualifier maps qualifiers to their timestamped values
 type VersionedQualifier map[string][]TimestampedValue
 
@@ -75,16 +84,8 @@ func (r *Row) PrettyPrint() string {
 			result += fmt.Sprintf("  qualifier: %s\n", qualifier)
 
 			for i, v := range values {
-				// Try to URL-decode the value
-				rawValue := v.GetString()
-				decodedValue, err := url.QueryUnescape(rawValue)
-				if err != nil {
-					// If it fails to decode, use the original value
-					decodedValue = rawValue
-				}
-
 				result += fmt.Sprintf("    value %d: %s, timestamp: %d\n",
-					i+1, decodedValue, v.Timestamp)
+					i+1, v.GetString(), v.Timestamp)
 			}
 		}
 	}
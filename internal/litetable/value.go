@@ -0,0 +1,66 @@
+package litetable
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Base64Key marks a binary value in JSON documents such as exports and imports:
+//
+//	{"$base64": "AAECAw=="}
+const Base64Key = "$base64"
+
+// ValueBytes converts a qualifier value to the bytes stored on the server. Byte slices
+// and strings are stored exactly as given, numbers and booleans use their textual form
+// and any other value is stored as JSON. An object of the form {"$base64": "..."} is
+// decoded to its binary value.
+func ValueBytes(v any) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, fmt.Errorf("value must not be null")
+	case []byte:
+		return val, nil
+	case string:
+		return []byte(val), nil
+	case json.Number:
+		return []byte(val.String()), nil
+	case float64:
+		return []byte(strconv.FormatFloat(val, 'f', -1, 64)), nil
+	case float32:
+		return []byte(strconv.FormatFloat(float64(val), 'f', -1, 32)), nil
+	case int:
+		return []byte(strconv.Itoa(val)), nil
+	case int32:
+		return []byte(strconv.FormatInt(int64(val), 10)), nil
+	case int64:
+		return []byte(strconv.FormatInt(val, 10)), nil
+	case bool:
+		return []byte(strconv.FormatBool(val)), nil
+	case map[string]any:
+		if encoded, ok := val[Base64Key].(string); ok && len(val) == 1 {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value: %w", Base64Key, err)
+			}
+			return decoded, nil
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return b, nil
+}
+
+// JSONValue returns a value suitable for a JSON document. Valid UTF-8 is returned as a
+// string; anything else is wrapped as {"$base64": "..."} so ValueBytes can restore it.
+func JSONValue(b []byte) any {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return map[string]any{Base64Key: base64.StdEncoding.EncodeToString(b)}
+}
@@ -0,0 +1,285 @@
+package logrotate
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxSizeMB  = 50
+	defaultMaxBackups = 5
+
+	// backupTimeFormat keeps rotated segments listed in order
+	backupTimeFormat = "20060102T150405"
+)
+
+// Config controls when a log is rolled over and how many old segments are kept
+type Config struct {
+	// MaxSize rolls the log over once it would grow past this many bytes (0 disables)
+	MaxSize int64
+	// MaxAge rolls the log over once its segment is this old (0 disables)
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated segments to keep (0 keeps all)
+	MaxBackups int
+	// Compress gzips rotated segments
+	Compress bool
+	// Supervisor pipes the server output through 'litetable service logwriter' so the log
+	// is rotated while the server runs, not only when it is started
+	Supervisor bool
+}
+
+// DefaultConfig is used for settings missing from litetable.conf
+func DefaultConfig() Config {
+	return Config{
+		MaxSize:    defaultMaxSizeMB * 1024 * 1024,
+		MaxBackups: defaultMaxBackups,
+		Compress:   true,
+		Supervisor: true,
+	}
+}
+
+// LoadConfig reads the log_* settings from litetable.conf, using defaults for missing keys
+func LoadConfig() (Config, error) {
+	cfg := DefaultConfig()
+
+	c, err := config.Read()
+	if errors.Is(err, config.ErrNotInstalled) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.MaxSize = int64(c.LogMaxSizeMB) * 1024 * 1024
+	cfg.MaxAge = c.LogMaxAge
+	cfg.MaxBackups = c.LogMaxBackups
+	cfg.Compress = c.LogCompress
+	cfg.Supervisor = c.LogSupervisor
+	return cfg, nil
+}
+
+// RotateIfNeeded rolls the log at path over when it is larger than MaxSize, or was last
+// written longer than MaxAge ago. It reports whether the log was rotated.
+func RotateIfNeeded(path string, cfg Config) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if info.Size() == 0 {
+		return false, nil
+	}
+
+	tooBig := cfg.MaxSize > 0 && info.Size() >= cfg.MaxSize
+	tooOld := cfg.MaxAge > 0 && time.Since(info.ModTime()) >= cfg.MaxAge
+	if !tooBig && !tooOld {
+		return false, nil
+	}
+
+	return true, Rotate(path, cfg)
+}
+
+// Rotate renames the log at path to a timestamped backup next to it, compresses the
+// backup if configured and removes backups beyond MaxBackups.
+func Rotate(path string, cfg Config) error {
+	backup := backupName(path, time.Now())
+	if err := os.Rename(path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log: %w", err)
+	}
+
+	if cfg.Compress {
+		if err := compress(backup); err != nil {
+			return err
+		}
+	}
+
+	return prune(path, cfg.MaxBackups)
+}
+
+// backupName returns e.g. litetable-20250512T132958.log for litetable.log, adding a
+// counter if a backup for the same second already exists.
+func backupName(path string, now time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	stamp := now.Format(backupTimeFormat)
+
+	name := fmt.Sprintf("%s-%s%s", base, stamp, ext)
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s-%s.%d%s", base, stamp, i, ext)
+	}
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// compress replaces path with path.gz
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to compress rotated log: %w", err)
+	}
+
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(path + ".gz")
+		return fmt.Errorf("failed to compress rotated log: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(path + ".gz")
+		return fmt.Errorf("failed to compress rotated log: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to compress rotated log: %w", err)
+	}
+
+	return os.Remove(path)
+}
+
+// Backups returns the rotated segments of the log at path, newest first
+func Backups(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	matches, err := filepath.Glob(base + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, m := range matches {
+		if strings.HasSuffix(m, ext) || strings.HasSuffix(m, ext+".gz") {
+			backups = append(backups, m)
+		}
+	}
+
+	// Order by when each segment was last written; names only break ties, since a counter
+	// added for backups within the same second does not sort after the plain name
+	modTimes := make(map[string]time.Time, len(backups))
+	for _, b := range backups {
+		if info, err := os.Stat(b); err == nil {
+			modTimes[b] = info.ModTime()
+		}
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		ti, tj := modTimes[backups[i]], modTimes[backups[j]]
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return backups[i] > backups[j]
+	})
+	return backups, nil
+}
+
+func prune(path string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	backups, err := Backups(path)
+	if err != nil {
+		return err
+	}
+
+	for _, old := range backups[min(keep, len(backups)):] {
+		if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old log %s: %w", old, err)
+		}
+	}
+	return nil
+}
+
+// Writer appends to a log file and rotates it when it grows past MaxSize or its segment
+// gets older than MaxAge. Callers should write whole lines so they are not split across
+// segments.
+type Writer struct {
+	mu       sync.Mutex
+	path     string
+	cfg      Config
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewWriter opens the log at path for appending
+func NewWriter(path string, cfg Config) (*Writer, error) {
+	w := &Writer{path: path, cfg: cfg}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	w.file, w.size, w.openedAt = f, info.Size(), time.Now()
+	return nil
+}
+
+// Write appends p, rotating first if p would not fit in the current segment
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tooBig := w.cfg.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.cfg.MaxSize
+	tooOld := w.cfg.MaxAge > 0 && w.size > 0 && time.Since(w.openedAt) >= w.cfg.MaxAge
+	if tooBig || tooOld {
+		if err := w.file.Close(); err != nil {
+			return 0, err
+		}
+		rotateErr := Rotate(w.path, w.cfg)
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+
+		// Keep logging even if rotation failed, the error is still reported
+		n, err := w.file.Write(p)
+		w.size += int64(n)
+		if err == nil {
+			err = rotateErr
+		}
+		return n, err
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current segment
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
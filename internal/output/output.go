@@ -0,0 +1,260 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/litetable"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+)
+
+// Format is the rendering used for query results
+type Format string
+
+const (
+	Text   Format = "text"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	YAML   Format = "yaml"
+	CSV    Format = "csv"
+	Table  Format = "table"
+)
+
+// Formats lists every supported output format
+var Formats = []Format{Text, JSON, NDJSON, YAML, CSV, Table}
+
+// ParseFormat validates a user provided format name
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+
+	names := make([]string, 0, len(Formats))
+	for _, f := range Formats {
+		names = append(names, string(f))
+	}
+	return "", fmt.Errorf("unsupported output format %q (supported: %s)", name,
+		strings.Join(names, ", "))
+}
+
+// Value is a single decoded version of a qualifier. Values that are not valid UTF-8 are
+// base64 encoded and marked with Encoding "base64".
+type Value struct {
+	Value     string    `json:"value"`
+	Encoding  string    `json:"encoding,omitempty"`
+	Timestamp int64     `json:"timestamp"`
+	Time      time.Time `json:"time"`
+}
+
+// Row is the machine-readable representation of a litetable.Row
+type Row struct {
+	Key      string                        `json:"key"`
+	Families map[string]map[string][]Value `json:"families"`
+}
+
+// Cell is a single flattened row/family/qualifier/version entry
+type Cell struct {
+	Key       string
+	Family    string
+	Qualifier string
+	Value
+}
+
+// Rows renders the query results to w in the requested format. Rows are sorted by key
+// so the output is stable between runs.
+func Rows(w io.Writer, format Format, rows map[string]*litetable.Row) error {
+	switch format {
+	case Text, "":
+		return writeText(w, rows)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(Convert(rows))
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		for _, row := range Convert(rows) {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case YAML:
+		return writeYAML(w, Convert(rows))
+	case CSV:
+		return writeCSV(w, Flatten(rows))
+	case Table:
+		return writeTable(w, Flatten(rows))
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// Convert decodes the rows into their machine-readable form, sorted by key
+func Convert(rows map[string]*litetable.Row) []Row {
+	result := make([]Row, 0, len(rows))
+	for _, key := range sortedKeys(rows) {
+		row := rows[key]
+		out := Row{
+			Key:      row.Key,
+			Families: make(map[string]map[string][]Value, len(row.Columns)),
+		}
+
+		for family, qualifiers := range row.Columns {
+			out.Families[family] = make(map[string][]Value, len(qualifiers))
+			for qualifier, values := range qualifiers {
+				decoded := make([]Value, 0, len(values))
+				for _, v := range values {
+					value := Value{
+						Value:     v.GetString(),
+						Timestamp: v.Timestamp,
+						Time:      v.Time(),
+					}
+					if !utf8.Valid(v.Value) {
+						value.Value = base64.StdEncoding.EncodeToString(v.Value)
+						value.Encoding = "base64"
+					}
+					decoded = append(decoded, value)
+				}
+				out.Families[family][qualifier] = decoded
+			}
+		}
+		result = append(result, out)
+	}
+
+	return result
+}
+
+// Flatten produces one cell per row, family, qualifier and version, sorted by key,
+// family and qualifier. Versions keep the order returned by the server.
+func Flatten(rows map[string]*litetable.Row) []Cell {
+	var cells []Cell
+	for _, row := range Convert(rows) {
+		for _, family := range sortedKeys(row.Families) {
+			qualifiers := row.Families[family]
+			for _, qualifier := range sortedKeys(qualifiers) {
+				for _, v := range qualifiers[qualifier] {
+					cells = append(cells, Cell{
+						Key:       row.Key,
+						Family:    family,
+						Qualifier: qualifier,
+						Value:     v,
+					})
+				}
+			}
+		}
+	}
+	return cells
+}
+
+func writeText(w io.Writer, rows map[string]*litetable.Row) error {
+	for i, key := range sortedKeys(rows) {
+		if i > 0 {
+			if _, err := fmt.Fprint(w, "--------------------\n\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", rows[key].PrettyPrint()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeYAML(w io.Writer, rows []Row) error {
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&b, "- key: %s\n", strconv.Quote(row.Key))
+		b.WriteString("  families:\n")
+		for _, family := range sortedKeys(row.Families) {
+			fmt.Fprintf(&b, "    %s:\n", strconv.Quote(family))
+			qualifiers := row.Families[family]
+			for _, qualifier := range sortedKeys(qualifiers) {
+				fmt.Fprintf(&b, "      %s:\n", strconv.Quote(qualifier))
+				for _, v := range qualifiers[qualifier] {
+					fmt.Fprintf(&b, "        - value: %s\n", strconv.Quote(v.Value))
+					if v.Encoding != "" {
+						fmt.Fprintf(&b, "          encoding: %s\n", v.Encoding)
+					}
+					fmt.Fprintf(&b, "          timestamp: %d\n", v.Timestamp)
+					fmt.Fprintf(&b, "          time: %s\n", v.Time.Format(time.RFC3339Nano))
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeCSV(w io.Writer, cells []Cell) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"key", "family", "qualifier", "value", "encoding", "timestamp", "time"}); err != nil {
+		return err
+	}
+
+	for _, c := range cells {
+		if err := cw.Write([]string{
+			c.Key,
+			c.Family,
+			c.Qualifier,
+			c.Value.Value,
+			c.Encoding,
+			strconv.FormatInt(c.Timestamp, 10),
+			c.Time.Format(time.RFC3339Nano),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeTable(w io.Writer, cells []Cell) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "KEY\tFAMILY\tQUALIFIER\tVALUE\tTIME"); err != nil {
+		return err
+	}
+
+	// Tabs and newlines would break the column alignment, so show them escaped
+	escaper := strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+	for _, c := range cells {
+		value := escaper.Replace(c.Value.Value)
+		if c.Encoding != "" {
+			value = c.Encoding + ":" + value
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			escaper.Replace(c.Key),
+			escaper.Replace(c.Family),
+			escaper.Replace(c.Qualifier),
+			value,
+			c.Time.Format(time.RFC3339)); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,306 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/dir"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Local is the built-in context that reads litetable.conf for the locally installed server
+	Local = "local"
+
+	contextsFile = "contexts.json"
+	contextEnv   = "LITETABLE_CONTEXT"
+)
+
+// TLS holds the transport security settings of a context
+type TLS struct {
+	Enabled            bool   `json:"enabled,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// Context is a named connection to a LiteTable server
+type Context struct {
+	Address  string `json:"address"`
+	RPCPort  string `json:"rpc_port"`
+	HTTPPort string `json:"http_port,omitempty"`
+	TLS      TLS    `json:"tls,omitempty"`
+}
+
+// Validate checks that the context describes a reachable server
+func (c *Context) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if err := validatePort(c.RPCPort); err != nil {
+		return fmt.Errorf("invalid RPC port: %w", err)
+	}
+	if c.HTTPPort != "" {
+		if err := validatePort(c.HTTPPort); err != nil {
+			return fmt.Errorf("invalid HTTP port: %w", err)
+		}
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return fmt.Errorf("a client certificate requires both a cert file and a key file")
+	}
+	return nil
+}
+
+// Config is the contents of contexts.json
+type Config struct {
+	Current  string              `json:"current,omitempty"`
+	Contexts map[string]*Context `json:"contexts"`
+}
+
+// Names returns the stored context names in sorted order, without the built-in local context
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Target is the resolved server a command should talk to
+type Target struct {
+	Name     string
+	Address  string
+	RPCPort  string
+	HTTPPort string
+	TLS      TLS
+	// ServerVersion is the installed version of the local server; it is not known for
+	// other contexts
+	ServerVersion string
+
+	// overridden is set when --server replaced the address
+	overridden bool
+}
+
+// IsLocal reports whether the target is the server installed on this machine, so files
+// in ~/.litetable such as the families file describe it
+func (t *Target) IsLocal() bool {
+	return t.Name == Local && !t.overridden
+}
+
+// RPCAddress returns the host:port used to dial the gRPC server
+func (t *Target) RPCAddress() string {
+	return net.JoinHostPort(t.Address, t.RPCPort)
+}
+
+// HTTPAddress returns the host:port of the server's HTTP endpoints
+func (t *Target) HTTPAddress() string {
+	return net.JoinHostPort(t.Address, t.HTTPPort)
+}
+
+// HTTPURL returns the URL of an HTTP endpoint, using https when the target uses TLS
+func (t *Target) HTTPURL(path string) string {
+	scheme := "http"
+	if t.TLS.Enabled {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, t.HTTPAddress(), path)
+}
+
+var (
+	overrideContext string
+	overrideServer  string
+)
+
+// SetOverride sets the --context and --server values given on the command line. Both
+// take precedence over the current context; an empty value leaves the default in place.
+func SetOverride(contextName, server string) {
+	overrideContext = contextName
+	overrideServer = server
+}
+
+// Load reads contexts.json. A missing file yields an empty configuration.
+func Load() (*Config, error) {
+	path, err := filePath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{Contexts: make(map[string]*Context)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read contexts file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse contexts file %s: %w", path, err)
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]*Context)
+	}
+
+	return cfg, nil
+}
+
+// Save writes contexts.json. The file may hold credential paths, so it is private to the user.
+func Save(cfg *Config) error {
+	path, err := filePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create LiteTable directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Resolve determines the server to talk to. The context is chosen from --context, then
+// LITETABLE_CONTEXT, then the current context, falling back to the local server described
+// by litetable.conf. A --server host[:port] override replaces the address and RPC port.
+func Resolve() (*Target, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name := overrideContext
+	if name == "" {
+		name = os.Getenv(contextEnv)
+	}
+	if name == "" {
+		name = cfg.Current
+	}
+
+	var target *Target
+	if name == "" || name == Local {
+		target, err = LocalTarget()
+	} else {
+		c, ok := cfg.Contexts[name]
+		if !ok {
+			return nil, fmt.Errorf("context %q does not exist; run 'litetable context list'", name)
+		}
+		target = &Target{
+			Name:     name,
+			Address:  c.Address,
+			RPCPort:  c.RPCPort,
+			HTTPPort: c.HTTPPort,
+			TLS:      c.TLS,
+		}
+	}
+
+	// A server override only needs the address, so a missing local config is not an error
+	if overrideServer != "" {
+		if err != nil {
+			target, err = &Target{Name: Local}, nil
+		}
+		if err := applyServer(target, overrideServer); err != nil {
+			return nil, err
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if target.TLS.CAFile != "" || target.TLS.CertFile != "" {
+		target.TLS.Enabled = true
+	}
+
+	return target, nil
+}
+
+// applyServer applies a --server host[:port] override to the target
+func applyServer(t *Target, server string) error {
+	// The override points at another server, whose version and HTTP port are not known, so
+	// the HTTP port falls back to the server default
+	t.ServerVersion = ""
+	t.HTTPPort = DefaultHTTPPort()
+	t.overridden = true
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		// No port given, only the address is replaced. Without a configured RPC port, e.g.
+		// when no local server is installed, the server default is used.
+		t.Address = server
+		if t.RPCPort == "" {
+			t.RPCPort = DefaultRPCPort()
+		}
+	} else {
+		if err := validatePort(port); err != nil {
+			return fmt.Errorf("invalid --server %q: %w", server, err)
+		}
+		t.Address, t.RPCPort = host, port
+	}
+
+	if t.Address == "" {
+		return fmt.Errorf("invalid --server %q: address is required", server)
+	}
+	return nil
+}
+
+// DefaultHTTPPort returns the HTTP port a server listens on unless configured otherwise
+func DefaultHTTPPort() string {
+	k, _ := config.Lookup(config.ServerPort)
+	return k.Default
+}
+
+// DefaultRPCPort returns the gRPC port a server listens on unless configured otherwise
+func DefaultRPCPort() string {
+	k, _ := config.Lookup(config.ServerRPCPort)
+	return k.Default
+}
+
+// LocalTarget reads the connection settings of the locally installed server
+func LocalTarget() (*Target, error) {
+	cfg, err := config.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server configuration: %w", err)
+	}
+
+	return &Target{
+		Name:          Local,
+		Address:       cfg.ServerAddress,
+		RPCPort:       strconv.Itoa(cfg.ServerRPCPort),
+		HTTPPort:      strconv.Itoa(cfg.ServerPort),
+		ServerVersion: cfg.ServerVersion,
+		TLS: TLS{
+			Enabled:            cfg.TLSEnabled,
+			CAFile:             cfg.TLSCAFile,
+			CertFile:           cfg.TLSCertFile,
+			KeyFile:            cfg.TLSKeyFile,
+			ServerName:         cfg.TLSServerName,
+			InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+		},
+	}, nil
+}
+
+func validatePort(port string) error {
+	p, err := strconv.Atoi(strings.TrimSpace(port))
+	if err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("%q is not a port between 1 and 65535", port)
+	}
+	return nil
+}
+
+func filePath() (string, error) {
+	ltDir, err := dir.GetLitetableDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(ltDir, contextsFile), nil
+}
@@ -0,0 +1,72 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveServerOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     string
+		server   string
+		wantAddr string
+		wantRPC  string
+		wantErr  bool
+	}{
+		{name: "host without config", server: "db.example.com",
+			wantAddr: "db.example.com", wantRPC: DefaultRPCPort()},
+		{name: "host and port without config", server: "db.example.com:50000",
+			wantAddr: "db.example.com", wantRPC: "50000"},
+		{name: "ipv6 host and port without config", server: "[::1]:50000",
+			wantAddr: "::1", wantRPC: "50000"},
+		{name: "host keeps the configured port", conf: "server_rpc_port = 50001\n",
+			server: "db.example.com", wantAddr: "db.example.com", wantRPC: "50001"},
+		{name: "port replaces the configured port", conf: "server_rpc_port = 50001\n",
+			server: "db.example.com:50002", wantAddr: "db.example.com", wantRPC: "50002"},
+		{name: "invalid port", server: "db.example.com:0", wantErr: true},
+		{name: "missing address", server: ":50000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv(contextEnv, "")
+			if tt.conf != "" {
+				ltDir := filepath.Join(home, ".litetable")
+				if err := os.MkdirAll(ltDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(ltDir, "litetable.conf"), []byte(tt.conf), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			SetOverride("", tt.server)
+			t.Cleanup(func() { SetOverride("", "") })
+
+			target, err := Resolve()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Resolve() with --server %q = %+v, want an error", tt.server, target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() with --server %q returned error: %v", tt.server, err)
+			}
+			if target.Address != tt.wantAddr || target.RPCPort != tt.wantRPC {
+				t.Errorf("Resolve() with --server %q = %s:%s, want %s:%s", tt.server,
+					target.Address, target.RPCPort, tt.wantAddr, tt.wantRPC)
+			}
+			if target.HTTPPort != DefaultHTTPPort() {
+				t.Errorf("HTTPPort = %q, want the default %q", target.HTTPPort, DefaultHTTPPort())
+			}
+			if target.IsLocal() {
+				t.Error("IsLocal() = true for an overridden server")
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package release installs the LiteTable server from prebuilt release archives.
+//
+// Releases follow the goreleaser layout: for tag vX.Y.Z the assets are
+// litetable-server_X.Y.Z_<os>_<arch>.tar.gz (.zip on Windows), a
+// litetable-server_X.Y.Z_checksums.txt file with one "<sha256>  <asset>" line per asset, and
+// optionally litetable-server_X.Y.Z_checksums.txt.sig, an ed25519 signature of the checksums
+// file encoded as base64.
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ProjectName prefixes every release asset
+	ProjectName = "litetable-server"
+
+	downloadTimeout = 5 * time.Minute
+)
+
+// ErrNoArtifact is returned when a release has no archive for the requested platform
+var ErrNoArtifact = errors.New("no prebuilt release archive for this platform")
+
+// Options describes where a release comes from and how it is verified
+type Options struct {
+	// Version is the release tag, e.g. v1.2.3
+	Version string
+	// BaseURL is where release assets are downloaded from; assets are fetched from
+	// <BaseURL>/<Version>/<asset>
+	BaseURL string
+	// Archive installs a local archive instead of downloading one
+	Archive string
+	// Checksums is the checksums file for a local Archive. When empty it is looked for next
+	// to the archive.
+	Checksums string
+	// PublicKey is an ed25519 public key (PEM or base64). When set, the checksums file must
+	// carry a valid signature.
+	PublicKey string
+	// GOOS and GOARCH select the platform of the archive
+	GOOS   string
+	GOARCH string
+}
+
+// Result describes an installed release
+type Result struct {
+	Asset  string
+	SHA256 string
+	Signed bool
+}
+
+// AssetName returns the archive name of a release for a platform
+func AssetName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", ProjectName, strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// ChecksumsName returns the name of the checksums file of a release
+func ChecksumsName(version string) string {
+	return fmt.Sprintf("%s_%s_checksums.txt", ProjectName, strings.TrimPrefix(version, "v"))
+}
+
+var archiveVersion = regexp.MustCompile(`^` + ProjectName + `_(\d+\.\d+\.\d+[^_]*)_[a-z0-9]+_[a-z0-9]+\.(?:tar\.gz|tgz|zip)$`)
+
+// VersionFromArchive returns the tag of a release archive named like AssetName
+func VersionFromArchive(path string) (string, bool) {
+	m := archiveVersion.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return "", false
+	}
+	return "v" + m[1], true
+}
+
+// Install fetches, verifies and unpacks the server binary of a release to dest. The
+// binary is replaced atomically, so a failed install leaves the existing one in place.
+func Install(opts Options, dest string) (*Result, error) {
+	tempDir, err := os.MkdirTemp("", "litetable-release")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive, checksums := opts.Archive, opts.Checksums
+	asset := AssetName(opts.Version, opts.GOOS, opts.GOARCH)
+	if archive == "" {
+		archive = filepath.Join(tempDir, asset)
+		if err := download(opts.assetURL(asset), archive); err != nil {
+			return nil, err
+		}
+		checksums = filepath.Join(tempDir, ChecksumsName(opts.Version))
+		if err := download(opts.assetURL(ChecksumsName(opts.Version)), checksums); err != nil {
+			if errors.Is(err, ErrNoArtifact) {
+				return nil, fmt.Errorf("release %s has no checksums file", opts.Version)
+			}
+			return nil, err
+		}
+	} else {
+		asset = filepath.Base(archive)
+		if checksums == "" {
+			if checksums, err = findChecksums(archive); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result := &Result{Asset: asset}
+	if opts.PublicKey != "" {
+		sig := checksums + ".sig"
+		if opts.Archive == "" {
+			if err := download(opts.assetURL(ChecksumsName(opts.Version)+".sig"), sig); err != nil {
+				if errors.Is(err, ErrNoArtifact) {
+					return nil, fmt.Errorf("release %s is not signed, but a public key is configured", opts.Version)
+				}
+				return nil, err
+			}
+		}
+		if err := verifySignature(checksums, sig, opts.PublicKey); err != nil {
+			return nil, err
+		}
+		result.Signed = true
+	}
+
+	if result.SHA256, err = verifyChecksum(archive, asset, checksums); err != nil {
+		return nil, err
+	}
+
+	if err := unpack(archive, dest); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (o Options) assetURL(name string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(o.BaseURL, "/"), o.Version, name)
+}
+
+// download saves url to path, returning ErrNoArtifact when it does not exist
+func download(url, path string) error {
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", url, ErrNoArtifact)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return f.Close()
+}
+
+// findChecksums looks for the checksums file of a local archive in its directory
+func findChecksums(archive string) (string, error) {
+	dir := filepath.Dir(archive)
+	if version, ok := VersionFromArchive(archive); ok {
+		path := filepath.Join(dir, ChecksumsName(version))
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*checksums.txt"))
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("no checksums file found next to %s; pass one with --checksums", archive)
+}
+
+// LatestVersion returns the tag of the newest release of a GitHub repository by following
+// its releases/latest redirect, which needs neither git nor the API rate limit.
+func LatestVersion(repoURL string) (string, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Head(strings.TrimSuffix(repoURL, "/") + "/releases/latest")
+	if err != nil {
+		return "", fmt.Errorf("failed to look up the latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	location := resp.Header.Get("Location")
+	_, tag, ok := strings.Cut(location, "/releases/tag/")
+	if !ok || tag == "" {
+		return "", fmt.Errorf("failed to look up the latest release: no release found")
+	}
+	return tag, nil
+}
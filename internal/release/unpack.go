@@ -0,0 +1,105 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// unpack extracts the server binary from a .tar.gz or .zip archive to dest. Other files
+// in the archive, such as the license, are skipped.
+func unpack(archive, dest string) error {
+	if strings.HasSuffix(archive, ".zip") {
+		return unpackZip(archive, dest)
+	}
+	return unpackTarGz(archive, dest)
+}
+
+func isServerBinary(name string) bool {
+	base := filepath.Base(name)
+	return base == ProjectName || base == ProjectName+".exe"
+}
+
+func unpackTarGz(archive, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && isServerBinary(hdr.Name) {
+			return writeBinary(tr, dest)
+		}
+	}
+	return fmt.Errorf("%s does not contain %s", filepath.Base(archive), ProjectName)
+}
+
+func unpackZip(archive, dest string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || !isServerBinary(file.Name) {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer rc.Close()
+		return writeBinary(rc, dest)
+	}
+	return fmt.Errorf("%s does not contain %s", filepath.Base(archive), ProjectName)
+}
+
+// writeBinary writes an executable next to dest and renames it into place
+func writeBinary(r io.Reader, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create bin directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "."+filepath.Base(dest)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write server binary: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write server binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write server binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make server executable: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to install server binary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package release
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// verifyChecksum compares the SHA-256 of archive with the entry for asset in the
+// checksums file and returns the digest.
+func verifyChecksum(archive, asset, checksums string) (string, error) {
+	expected, err := lookupChecksum(checksums, asset)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(archive)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset, expected, actual)
+	}
+	return actual, nil
+}
+
+// lookupChecksum finds the digest of asset in a "<sha256>  <name>" checksums file
+func lookupChecksum(checksums, asset string) (string, error) {
+	f, err := os.Open(checksums)
+	if err != nil {
+		return "", fmt.Errorf("failed to open checksums file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sha256sum marks binary mode with a leading '*' on the name
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == asset {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read checksums file: %w", err)
+	}
+	return "", fmt.Errorf("%s is not listed in %s", asset, checksums)
+}
+
+// verifySignature checks the ed25519 signature sigPath of the checksums file
+func verifySignature(checksums, sigPath, publicKey string) error {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(checksums)
+	if err != nil {
+		return fmt.Errorf("failed to read checksums file: %w", err)
+	}
+
+	rawSig, err := os.ReadFile(sigPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("signature %s not found, but a public key is configured", sigPath)
+		}
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(rawSig)))
+	if err != nil {
+		// Accept a raw binary signature as well
+		sig = rawSig
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("invalid signature for %s", checksums)
+	}
+	return nil
+}
+
+// parsePublicKey reads an ed25519 public key given as a file path or inline value, either
+// PEM encoded (PKIX) or as base64 of the 32 raw key bytes.
+func parsePublicKey(value string) (ed25519.PublicKey, error) {
+	data := []byte(value)
+	if content, err := os.ReadFile(value); err == nil {
+		data = content
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		key, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key: only ed25519 keys are supported")
+		}
+		return key, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: expected a PEM file or a base64 ed25519 key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
@@ -1,11 +1,20 @@
 package server
 
 import (
+	"context"
 	"fmt"
-	"github.com/litetable/litetable-cli/internal/litetable"
+	"github.com/litetable/litetable-cli/internal/profile"
 	"github.com/litetable/litetable-db/pkg/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pingFamily and pingRowKey name a row that is not expected to exist; reading it is a
+// cheap way to get an answer from the server.
+const (
+	pingFamily = "__litetable_cli_ping"
+	pingRowKey = "__ping"
 )
 
 type GrpcClient struct {
@@ -13,25 +22,29 @@ type GrpcClient struct {
 	client proto.LitetableServiceClient
 
 	rpcConnString string
+	tls           bool
 }
 
-// NewClient creates a new LiteTable gRPC client
+// NewClient creates a new LiteTable gRPC client for the server selected by the active
+// context (see profile.Resolve).
 func NewClient() (*GrpcClient, error) {
-	// TODO: allow the setting of secure credentials
-	serverAddress, err := litetable.GetFromConfig(litetable.ServerAddress)
+	target, err := profile.Resolve()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get server address: %w", err)
+		return nil, err
 	}
 
-	serverRPCPort, err := litetable.GetFromConfig(litetable.ServerRPCPort)
+	return NewClientForTarget(target)
+}
+
+// NewClientForTarget creates a new LiteTable gRPC client for a resolved target
+func NewClientForTarget(target *profile.Target) (*GrpcClient, error) {
+	creds, err := transportCredentials(target.TLS)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get server RPC port: %w", err)
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
 	}
 
-	connString := fmt.Sprintf("%s:%s", serverAddress, serverRPCPort)
-	conn, err := grpc.NewClient(connString,
-		grpc.WithTransportCredentials(insecure.
-			NewCredentials()))
+	connString := target.RPCAddress()
+	conn, err := grpc.NewClient(connString, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +54,7 @@ func NewClient() (*GrpcClient, error) {
 		rpcConnString: connString,
 		conn:          conn,
 		client:        ltClient,
+		tls:           target.TLS.Enabled,
 	}, nil
 }
 
@@ -52,3 +66,27 @@ func (g *GrpcClient) Close() error {
 
 	return nil
 }
+
+// Ping checks that the server answers RPCs. Any response produced by the server counts,
+// including an error for the unknown family it reads; only transport failures and timeouts
+// are returned.
+func (g *GrpcClient) Ping(ctx context.Context) error {
+	_, err := g.client.Read(ctx, &proto.ReadRequest{
+		RowKey:    pingRowKey,
+		QueryType: proto.QueryType_EXACT,
+		Family:    pingFamily,
+	})
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return g.wrapErr(err)
+	}
+	return nil
+}
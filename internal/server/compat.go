@@ -0,0 +1,60 @@
+package server
+
+import (
+	"runtime/debug"
+)
+
+// protoModule is the server module whose generated proto package the CLI is built with
+const protoModule = "github.com/litetable/litetable-db/pkg"
+
+// testedServers lists the server releases that have been tested with the proto package
+// pinned in go.mod. Only add a release once it has been verified against that version,
+// and update the list together with the litetable-db/pkg dependency.
+var testedServers []string
+
+// Compatibility is what the CLI knows about working with a server. The server does not
+// report its version, so only a version recorded by 'service init' can be checked.
+type Compatibility struct {
+	// ServerVersion is the checked version; empty when it is not known, e.g. for remote
+	// contexts and --server
+	ServerVersion string
+	// Tested is true when the server release has been tested with the CLI's proto package
+	Tested bool
+}
+
+// Status is "compatible" for a tested server release and "unknown" otherwise
+func (c *Compatibility) Status() string {
+	if c.Tested {
+		return "compatible"
+	}
+	return "unknown"
+}
+
+// ProtoVersion returns the version of the proto package the CLI was built with
+func ProtoVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == protoModule {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
+
+// CheckCompatibility looks a server version up in the tested server releases
+func CheckCompatibility(serverVersion string) *Compatibility {
+	c := &Compatibility{ServerVersion: serverVersion}
+	for _, v := range testedServers {
+		if v == serverVersion {
+			c.Tested = true
+			break
+		}
+	}
+	return c
+}
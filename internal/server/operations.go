@@ -3,12 +3,19 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/litetable/litetable-cli/internal/litetable"
 	"github.com/litetable/litetable-db/pkg/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
 )
 
 var ErrRowNotFound = errors.New("row not found")
 
+// ErrFamilyExists is returned by CreateFamilies when a family already exists
+var ErrFamilyExists = errors.New("family already exists")
+
 type QueryType = proto.QueryType
 
 var Read = proto.QueryType_EXACT
@@ -34,10 +41,10 @@ func (g *GrpcClient) Read(ctx context.Context, p *ReadParams) (map[string]*litet
 		Latest:     p.Latest,
 	})
 	if err != nil {
-		if errors.As(err, &ErrRowNotFound) {
+		if status.Code(err) == codes.NotFound {
 			return nil, ErrRowNotFound
 		}
-		return nil, err
+		return nil, g.wrapErr(err)
 	}
 
 	rows := data.GetRows()
@@ -46,6 +53,8 @@ func (g *GrpcClient) Read(ctx context.Context, p *ReadParams) (map[string]*litet
 	return result, nil
 }
 
+// Qualifier is a single column to write. Value may be a []byte, a string, a number, a
+// boolean or any JSON-encodable value; see litetable.ValueBytes for how it is stored.
 type Qualifier struct {
 	Name  string
 	Value any
@@ -63,14 +72,18 @@ func (g *GrpcClient) Write(ctx context.Context, p *WriteParams) (map[string]*lit
 		Family: p.Family,
 	}
 	for _, q := range p.Qualifiers {
+		value, err := litetable.ValueBytes(q.Value)
+		if err != nil {
+			return nil, fmt.Errorf("qualifier %s: %w", q.Name, err)
+		}
 		params.Qualifiers = append(params.Qualifiers, &proto.ColumnQualifier{
 			Name:  q.Name,
-			Value: []byte(q.Value.(string)),
+			Value: value,
 		})
 	}
 	res, err := g.client.Write(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, g.wrapErr(err)
 	}
 
 	rows := res.GetRows()
@@ -96,7 +109,7 @@ func (g *GrpcClient) Delete(ctx context.Context, p *DeleteParams) error {
 	}
 	_, err := g.client.Delete(ctx, params)
 	if err != nil {
-		return err
+		return g.wrapErr(err)
 	}
 
 	return nil
@@ -113,8 +126,25 @@ func (g *GrpcClient) CreateFamilies(ctx context.Context, p *CreateFamilyParams)
 
 	_, err := g.client.CreateFamily(ctx, params)
 	if err != nil {
-		return err
+		if status.Code(err) == codes.AlreadyExists ||
+			strings.Contains(strings.ToLower(status.Convert(err).Message()), "already exist") {
+			return fmt.Errorf("%w: %v", ErrFamilyExists, err)
+		}
+		return g.wrapErr(err)
 	}
 
 	return nil
 }
+
+// EnsureFamilies creates each family on the server, treating families that already exist
+// as created. The server is asked directly because the local families file only describes
+// the locally installed server.
+func (g *GrpcClient) EnsureFamilies(ctx context.Context, families []string) error {
+	for _, family := range families {
+		err := g.CreateFamilies(ctx, &CreateFamilyParams{Families: []string{family}})
+		if err != nil && !errors.Is(err, ErrFamilyExists) {
+			return fmt.Errorf("family %s: %w", family, err)
+		}
+	}
+	return nil
+}
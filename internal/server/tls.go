@@ -0,0 +1,99 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/litetable/litetable-cli/internal/config"
+	"github.com/litetable/litetable-cli/internal/profile"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"os"
+	"strings"
+)
+
+// transportCredentials builds the gRPC transport credentials for the TLS settings.
+func transportCredentials(c profile.TLS) (credentials.TransportCredentials, error) {
+	if !c.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsCfg, err := TLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsCfg), nil
+}
+
+// TLSConfig builds the client TLS configuration for the TLS settings, which the gRPC
+// connection and the HTTP health checks share.
+func TLSConfig(c profile.TLS) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %s: %w", c.CAFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in CA bundle %s", c.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	// mTLS requires both halves of the client key pair
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, fmt.Errorf("both %s and %s must be set to use a client certificate",
+				config.TLSCertFile, config.TLSKeyFile)
+		}
+
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+// wrapErr turns opaque transport failures into errors that point at the TLS settings
+// most likely responsible for them. Any other error is returned unchanged.
+func (g *GrpcClient) wrapErr(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unavailable {
+		return err
+	}
+
+	msg := st.Message()
+	switch {
+	case strings.Contains(msg, "x509:"):
+		return fmt.Errorf("TLS handshake with %s failed: server certificate could not be "+
+			"verified; check %s and %s: %w", g.rpcConnString, config.TLSCAFile,
+			config.TLSServerName, err)
+	case strings.Contains(msg, "authentication handshake failed"),
+		strings.Contains(msg, "tls:"):
+		return fmt.Errorf("TLS handshake with %s failed; check that the server has TLS "+
+			"enabled and that %s/%s are accepted by it: %w", g.rpcConnString,
+			config.TLSCertFile, config.TLSKeyFile, err)
+	case !g.tls && (strings.Contains(msg, "server preface") ||
+		strings.Contains(msg, "connection reset")):
+		return fmt.Errorf("connection to %s was closed during setup; the server may "+
+			"require TLS (set %s = true): %w", g.rpcConnString, config.TLSEnabled, err)
+	}
+
+	return err
+}